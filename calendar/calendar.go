@@ -0,0 +1,105 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+)
+
+// FirstYear is the first Advent of Code event.
+const FirstYear = 2015
+
+// Eastern is the fixed UTC-5 offset AoC unlocks puzzles on. December never observes daylight savings.
+var Eastern = time.FixedZone("EST", -5*60*60)
+
+// DaysIn returns how many puzzles a year's event has. From 2025 onward, events run for 12 days.
+func DaysIn(year uint) uint {
+	if year >= 2025 {
+		return 12
+	}
+
+	return 25
+}
+
+// Parts returns how many parts a day's puzzle has. The final day of every event only has one.
+func Parts(day, year uint) int {
+	if day == DaysIn(year) {
+		return 1
+	}
+
+	return 2
+}
+
+// UnlockTime returns the moment a puzzle becomes available.
+func UnlockTime(year, day uint) time.Time {
+	return time.Date(int(year), time.December, int(day), 0, 0, 0, 0, Eastern)
+}
+
+func ValidateYear(year uint) error {
+	if year < FirstYear {
+		return fmt.Errorf("year %d is invalid: Advent of Code started in %d", year, FirstYear)
+	}
+
+	return nil
+}
+
+// ValidateDay checks the day exists in the year's event, regardless of whether it has unlocked yet.
+func ValidateDay(day, year uint) error {
+	if err := ValidateYear(year); err != nil {
+		return err
+	}
+
+	if day < 1 || day > DaysIn(year) {
+		return fmt.Errorf("day %d is invalid: %d has days 1 through %d", day, year, DaysIn(year))
+	}
+
+	return nil
+}
+
+// ValidateUnlocked checks the day exists and its puzzle is available as of now.
+func ValidateUnlocked(day, year uint, now time.Time) error {
+	if err := ValidateDay(day, year); err != nil {
+		return err
+	}
+
+	if at := UnlockTime(year, day); now.Before(at) {
+		return fmt.Errorf("day %d/%d has not unlocked yet: it unlocks in %s", year, day, at.Sub(now).Round(time.Second).String())
+	}
+
+	return nil
+}
+
+// Next returns the puzzle after the given one, rolling over into the next year after its last day.
+func Next(day, year uint) (uint, uint) {
+	if day >= DaysIn(year) {
+		return 1, year + 1
+	}
+
+	return day + 1, year
+}
+
+// NextUnlock returns the first puzzle unlocking strictly after now.
+func NextUnlock(now time.Time) (day, year uint, at time.Time) {
+	year = uint(now.In(Eastern).Year())
+
+	for day = 1; day <= DaysIn(year); day++ {
+		at = UnlockTime(year, day)
+		if at.After(now) {
+			return day, year, at
+		}
+	}
+
+	return 1, year + 1, UnlockTime(year+1, 1)
+}
+
+// LatestUnlocked returns the most recently unlocked puzzle as of now.
+func LatestUnlocked(now time.Time) (day, year uint) {
+	year = uint(now.In(Eastern).Year())
+
+	for day = DaysIn(year); day >= 1; day-- {
+		if !now.Before(UnlockTime(year, day)) {
+			return day, year
+		}
+	}
+
+	return DaysIn(year - 1), year - 1
+}
@@ -4,16 +4,19 @@ import (
 	_ "embed"
 	"errors"
 	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/calendar"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
 	"github.com/Riven-Spell/advent_of_code_forever/inputs"
 	"github.com/Riven-Spell/advent_of_code_forever/solutions"
 	"github.com/Riven-Spell/advent_of_code_forever/util"
 	"github.com/spf13/cobra"
+	"io"
 	"strings"
 )
 
 var cacheArgs = struct {
-	Day             uint // 1 <= day <= 25
-	Year            uint // 2015 <= day
+	Day             uint // validated against calendar.DaysIn
+	Year            uint // calendar.FirstYear <= year
 	Replace         bool
 	InputComplexity uint64
 	Mode            string
@@ -44,29 +47,37 @@ var cache = &cobra.Command{
 			cDay++
 		}
 
-		var err error
+		err := calendar.ValidateDay(cDay, cYear)
+		if err != nil {
+			return err
+		}
+
 		cache := inputs.Cache
 		switch strings.ToLower(cacheArgs.Mode) {
 		case "delete":
 			err = cache.DeleteInput(cDay, cYear)
 		case "generate":
 			day := solutions.Index.Get(cDay, cYear)
-			if day != nil || day.Generator == nil {
+			if day == nil || !day.HasGenerator() {
 				err = fmt.Errorf("could not generate input: no generator present for day %d/%d", cYear, cDay)
+				break
 			}
 
-			complexity := cacheArgs.InputComplexity
-			if complexity == 0 {
-				complexity = day.DefaultComplexity
-			}
+			var solution *inputs.Solution
+			err = cache.WriteInput(cDay, cYear, cacheArgs.Replace, func(w io.Writer) error {
+				var err error
+				solution, err = day.Generate(cacheArgs.InputComplexity, w)
+				return err
+			})
 
-			inputData, solutions := day.Generator(complexity) // todo: complexity
-			err = cache.PutInput(cDay, cYear, strings.NewReader(inputData), cacheArgs.Replace)
-			if err == nil && solutions != nil {
-				err = cache.PutSolution(cDay, cYear, *solutions, cacheArgs.Replace)
+			if err == nil && solution != nil {
+				err = cache.PutSolution(cDay, cYear, *solution, cacheArgs.Replace)
 			}
 		case "download":
-			err = cache.DownloadInput(cDay, cYear, cacheArgs.Replace)
+			err = calendar.ValidateUnlocked(cDay, cYear, core.SystemClock.Now())
+			if err == nil {
+				err = cache.DownloadInput(cDay, cYear, cacheArgs.Replace)
+			}
 		default:
 			return errors.New("unknown cache operation: " + strings.ToLower(cacheArgs.Mode))
 		}
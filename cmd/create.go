@@ -1,128 +1,248 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"github.com/Riven-Spell/advent_of_code_forever/solutions"
+	"github.com/Riven-Spell/advent_of_code_forever/calendar"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
+	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"github.com/Riven-Spell/advent_of_code_forever/plugin"
 	"github.com/Riven-Spell/advent_of_code_forever/solutions/solution_templates"
+	"github.com/Riven-Spell/advent_of_code_forever/transaction"
 	"github.com/Riven-Spell/advent_of_code_forever/util"
 	"github.com/spf13/cobra"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 )
 
 var createArgs = struct {
-	Next    string
-	Day     uint // 1 <= day <= 25
-	Year    uint // 2015 <= day
-	Replace bool
+	Next          string
+	Day           uint // validated against calendar.DaysIn
+	Year          uint // calendar.FirstYear <= year
+	Replace       bool
+	Template      string
+	NoVerify      bool
+	WithGenerator bool
+	Shared        string
+	Use           []string
 }{}
 
+type createOptions struct {
+	Template      string   // name of the solution template
+	Verify        bool     // build the solution code afterward, rolling back if it fails
+	WithGenerator bool     // scaffold a starter input generator
+	Use           []string // names of the year's shared packages to import
+}
+
+// latestDay finds the last day with code in the project at workDir, within year unless it is 0.
+// With none, it is day 0 of year (or of the latest event), so the next day is that year's first.
+func latestDay(workDir string, year uint) (day, lastYear uint, err error) {
+	days, err := plugin.Days(workDir)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// sorted, so the last match is the latest.
+	for _, d := range days {
+		if year == 0 || d.Year == year {
+			day, lastYear = d.Day, d.Year
+		}
+	}
+
+	if lastYear == 0 {
+		_, lastYear = calendar.LatestUnlocked(core.SystemClock.Now())
+		lastYear = util.Ternary(year != 0, year, lastYear)
+	}
+
+	return day, lastYear, nil
+}
+
+// createDay scaffolds a day's package from a solution template and regenerates the importer.
+func createDay(workDir string, cDay, cYear uint, opts createOptions) error {
+	dayTemp := solution_templates.SolutionTemplateInfill{
+		Day:      cDay,
+		Year:     cYear,
+		Package:  fmt.Sprintf("day%d", cDay),
+		FinalDay: calendar.Parts(cDay, cYear) < 2,
+	}
+	dayTemp.FillFromCache(inputs.Cache)
+	if opts.WithGenerator {
+		dayTemp.AddGenerator(inputs.Cache)
+	}
+
+	use := append([]string{}, opts.Use...)
+	sort.Strings(use)
+	for _, name := range use {
+		shared, err := solution_templates.FindShared(workDir, cYear, name)
+		if err != nil {
+			return err
+		}
+		dayTemp.Shared = append(dayTemp.Shared, shared)
+	}
+
+	// Render before touching the filesystem, so a bad template leaves nothing behind.
+	tmpl, err := solution_templates.LoadSolutionTemplate(workDir, opts.Template)
+	if err != nil {
+		return err
+	}
+
+	code, err := solution_templates.RenderSolution(tmpl, dayTemp)
+	if err != nil {
+		return err
+	}
+
+	solutionsPackage := filepath.Join(workDir, "solutions/solution_code")
+	dayPackage := filepath.Join(solutionsPackage, fmt.Sprint(cYear), dayTemp.Package)
+
+	tx, err := beginTransaction(fmt.Sprintf("create %d/%d", cYear, cDay))
+	if err != nil {
+		return err
+	}
+
+	err = writeDay(tx, workDir, dayPackage, dayTemp.Package+".go", code)
+	if err == nil && opts.Verify {
+		err = solution_templates.VerifyBuild(workDir)
+	}
+
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w\nrollback failed: %s", err, rbErr.Error())
+		}
+
+		return fmt.Errorf("%w\nrolled back day %d/%d", err, cYear, cDay)
+	}
+
+	return tx.Commit()
+}
+
+// writeDay writes a day's code and regenerates the importer, tracking both in tx.
+func writeDay(tx *transaction.Transaction, workDir, dayPackage, fileName string, code []byte) error {
+	importerName := filepath.Join(workDir, "solutions/solution_code/importer.go")
+	for _, path := range []string{dayPackage, importerName} {
+		if err := tx.Track(path); err != nil {
+			return err
+		}
+	}
+
+	err := os.MkdirAll(dayPackage, 0755)
+	if err != nil {
+		return fmt.Errorf("cannot create folders: %w", err)
+	}
+
+	// Write the template code
+	codeFileName := filepath.Join(dayPackage, fileName)
+	err = os.WriteFile(codeFileName, code, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot write file: %w", err)
+	}
+
+	// Generate the importer code
+	err = solution_templates.UpdateImporter(workDir)
+	if err != nil {
+		return fmt.Errorf("failed to update importer: %w", err)
+	}
+
+	return nil
+}
+
 var create = &cobra.Command{
-	Use:   "create {--next day/year | --day <day> --year <year>}",
+	Use:   "create {--next day/year | --day <day> --year <year> | --shared <name> [--year <year>]}",
 	Short: "Create a new day or year.",
 	Long:  "Attempts to create a new day/year if not present.",
 
 	RunE: func(cmd *cobra.Command, args []string) error {
-		codeDir, err := util.CodeDirName()
+		workDir, err := projectRoot()
 		if err != nil {
-			fmt.Printf("failed to get code directory: %w\n", err)
+			fmt.Println(err.Error())
 			return nil
 		}
-		workDir, err := os.Getwd()
+
+		cDay, cYear, err := latestDay(workDir, 0)
 		if err != nil {
-			if err != nil {
-				fmt.Printf("failed to get pwd: %w\n", err)
+			return err
+		}
+
+		opts := createOptions{
+			Template:      createArgs.Template,
+			Verify:        !createArgs.NoVerify,
+			WithGenerator: createArgs.WithGenerator,
+			Use:           createArgs.Use,
+		}
+
+		if createArgs.Shared != "" {
+			if len(createArgs.Use) != 0 || createArgs.WithGenerator {
+				return errors.New("--use and --with-generator apply to days, and can't be used with --shared")
+			}
+
+			if createArgs.Year != 0 {
+				cYear = createArgs.Year
+			}
+
+			if err := calendar.ValidateYear(cYear); err != nil {
+				return err
+			}
+
+			if _, err := os.Stat(solution_templates.SharedPackageDir(workDir, cYear, createArgs.Shared)); err == nil && !createArgs.Replace {
+				fmt.Printf("Not overwriting shared package %d/%s as it already exists.", cYear, createArgs.Shared)
 				return nil
 			}
-		}
 
-		if filepath.Dir(codeDir) != workDir {
-			fmt.Printf("cannot generate code unless in the source directory\n")
+			err = createShared(workDir, cYear, createArgs.Shared, opts)
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+
 			return nil
 		}
 
-		dayTemp := solution_templates.SolutionTemplateInfill{}
-		cDay, cYear := solutions.Index.GetCurrentDay()
 		mode := strings.ToLower(strings.TrimSpace(createArgs.Next))
 
 		if createArgs.Day != 0 || createArgs.Year != 0 {
 			mode = ""
 
+			if createArgs.Year != 0 {
+				cYear = createArgs.Year
+			}
+
 			if createArgs.Day != 0 {
 				cDay = createArgs.Day
 			} else {
+				cDay, _, err = latestDay(workDir, cYear)
+				if err != nil {
+					return err
+				}
 				mode = "day"
 			}
-
-			if createArgs.Year != 0 {
-				cYear = createArgs.Year
-			}
 		}
 
 		// select the correct day/year
 		switch mode {
+		case "":
+			// explicitly specified
 		case "day":
-			cDay++
-			if cDay <= 25 {
-				break
-			}
-			fallthrough
+			cDay, cYear = calendar.Next(cDay, cYear)
 		case "year":
-			cYear++
-			cDay = 1
+			cDay, cYear = 1, cYear+1
 		default:
 			return fmt.Errorf("no such next mode '%s'", mode)
 		}
 
-		if cDay == 0 {
-			cDay++
+		if err := calendar.ValidateDay(cDay, cYear); err != nil {
+			return err
 		}
 
-		if !createArgs.Replace {
-			// check we're not overwriting anything
-			if solutions.Index.Get(cDay, cYear) != nil {
-				fmt.Printf("Not overwriting day %d/%d as it already exists.", cYear, cDay)
-				return nil
-			}
-		}
-
-		dayTemp.Day = cDay
-		dayTemp.Year = cYear
-		dayTemp.Package = fmt.Sprintf("day%d", cDay)
-
-		solutionsPackage := filepath.Join(workDir, "solutions/solution_code")
-		dayPackage := filepath.Join(solutionsPackage, fmt.Sprint(cYear), dayTemp.Package)
-		err = os.MkdirAll(dayPackage, 0755)
-		if err != nil {
-			fmt.Printf("cannot create folders: %s\n", err.Error())
+		// check we're not overwriting anything
+		dayDir := filepath.Join(workDir, "solutions/solution_code", fmt.Sprint(cYear), fmt.Sprintf("day%d", cDay))
+		if _, err := os.Stat(dayDir); err == nil && !createArgs.Replace {
+			fmt.Printf("Not overwriting day %d/%d as it already exists; pass --replace to overwrite it.\n", cYear, cDay)
 			return nil
 		}
 
-		// Generate the template code
-		{
-			codeFileName := filepath.Join(dayPackage, dayTemp.Package+".go")
-
-			f, err := os.OpenFile(codeFileName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-			if err != nil {
-				fmt.Printf("cannot open file: %s\n", err.Error())
-				return nil
-			}
-
-			err = solution_templates.SolutionTemplate.Execute(f, dayTemp)
-			if err != nil {
-				fmt.Printf("cannot fill template: %s\n", err.Error())
-				return nil
-			}
-
-			_ = f.Close()
-		}
-
-		// Generate the importer code
-		err = solution_templates.UpdateImporter()
+		err = createDay(workDir, cDay, cYear, opts)
 		if err != nil {
-			fmt.Printf("failed to update importer: %s\n", err.Error())
-			return nil
+			fmt.Println(err.Error())
 		}
 
 		return nil
@@ -131,9 +251,15 @@ var create = &cobra.Command{
 
 func init() {
 	create.PersistentFlags().BoolVar(&createArgs.Replace, "replace", false, "Replace an existing day? (default: false)")
-	create.PersistentFlags().StringVar(&createArgs.Next, "next", "day", "Create the next day or year? Falls back to year if already on day 25 of last year.")
-	create.PersistentFlags().UintVar(&createArgs.Day, "day", 0, "Specify a day to create (1-25)")
+	create.PersistentFlags().StringVar(&createArgs.Next, "next", "day", "Create the next day or year? Falls back to year if already on the last day of the year.")
+	create.PersistentFlags().UintVar(&createArgs.Day, "day", 0, "Specify a day to create (1-25, or 1-12 from 2025)")
 	create.PersistentFlags().UintVar(&createArgs.Year, "year", 0, "Specify a year to create (2015-onward). Current year assumed if not specified.")
 
+	create.PersistentFlags().BoolVar(&createArgs.WithGenerator, "with-generator", false, "Scaffold an input generator, shaped like the cached input if there is one. (default: false)")
+	create.PersistentFlags().StringVar(&createArgs.Shared, "shared", "", "Create solution_code/<year>/shared/<name>, a package shared between the year's days, instead of a day.")
+	create.PersistentFlags().StringSliceVar(&createArgs.Use, "use", nil, "Shared packages of the year (see --shared) for the new day to import.")
+	create.PersistentFlags().BoolVar(&createArgs.NoVerify, "no-verify", false, "Skip building the solution code after creating the day. (default: false)")
+	create.PersistentFlags().StringVar(&createArgs.Template, "template", solution_templates.DefaultSolutionTemplate, "Name of the solution template to use. Looks for <name>.go.template in .aocf/templates, then the user config dir's aocf/templates.")
+
 	RootCmd.AddCommand(create)
 }
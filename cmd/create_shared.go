@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions/solution_templates"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// createShared scaffolds solution_code/<year>/shared/<name>, a package for code shared between the year's days, with an example function and its test.
+// It registers no days, so the importer leaves it alone; days import it with `aocf create --use <name>`.
+func createShared(workDir string, cYear uint, name string, opts createOptions) error {
+	if !token.IsIdentifier(name) || strings.ToLower(name) != name {
+		return fmt.Errorf("'%s' is not a valid package name; use a lowercase identifier", name)
+	}
+
+	infill := solution_templates.SharedTemplateInfill{
+		Package:  name,
+		Year:     cYear,
+		TestName: string(unicode.ToUpper(rune(name[0]))) + name[1:],
+	}
+
+	// both files are rendered up front, as in createDay.
+	files := map[string][]byte{}
+	for fileName, templateName := range map[string]string{
+		name + ".go":      solution_templates.SharedTemplateName,
+		name + "_test.go": solution_templates.SharedTestTemplateName,
+	} {
+		tmpl, err := solution_templates.LoadSolutionTemplate(workDir, templateName)
+		if err != nil {
+			return err
+		}
+
+		files[fileName], err = solution_templates.RenderSolution(tmpl, infill)
+		if err != nil {
+			return err
+		}
+	}
+
+	sharedPackage := solution_templates.SharedPackageDir(workDir, cYear, name)
+
+	tx, err := beginTransaction(fmt.Sprintf("create %d/%s/%s", cYear, solution_templates.SharedDir, name))
+	if err != nil {
+		return err
+	}
+
+	err = tx.Track(sharedPackage)
+	if err == nil {
+		err = os.MkdirAll(sharedPackage, 0755)
+	}
+
+	for fileName, code := range files {
+		if err != nil {
+			break
+		}
+
+		err = os.WriteFile(filepath.Join(sharedPackage, fileName), code, 0644)
+	}
+
+	if err == nil && opts.Verify {
+		err = solution_templates.VerifyBuild(workDir)
+	}
+
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w\nrollback failed: %s", err, rbErr.Error())
+		}
+
+		return fmt.Errorf("%w\nrolled back shared package %s", err, name)
+	}
+
+	return tx.Commit()
+}
@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/calendar"
 	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions"
 	"github.com/Riven-Spell/advent_of_code_forever/solutions/solution_templates"
+	"github.com/Riven-Spell/advent_of_code_forever/transaction"
 	"github.com/Riven-Spell/advent_of_code_forever/util"
 	"github.com/spf13/cobra"
 	"os"
@@ -11,39 +14,51 @@ import (
 )
 
 var deleteArgs = struct {
-	Year uint
-	Day  uint
+	Year     uint
+	Day      uint
+	NoVerify bool
 }{}
 
+// deleteDay moves a day or year's code into the trash and regenerates the importer, tracking both in tx.
+func deleteDay(tx *transaction.Transaction, workDir, rmTarget string) error {
+	err := tx.Track(filepath.Join(workDir, "solutions/solution_code/importer.go"))
+	if err != nil {
+		return err
+	}
+
+	err = tx.Remove(rmTarget)
+	if err != nil {
+		return fmt.Errorf("failed to remove directory %s: %w", rmTarget, err)
+	}
+
+	// Generate the importer code
+	err = solution_templates.UpdateImporter(workDir)
+	if err != nil {
+		return fmt.Errorf("failed to update importer: %w", err)
+	}
+
+	return nil
+}
+
 var deleteCMD = &cobra.Command{
 	Use:   "delete [--day <day>] --year <year>",
-	Short: "Delete an existing day's code. WARNING: this action is irrecoverable.",
+	Short: "Delete an existing day's code. Can be reverted with `aocf undo`.",
+	Long:  "Moves a day or year's code, cached inputs, solutions and puzzle text into the trash under the cache directory. If the remaining solution code no longer builds, the code is restored and the build errors are printed.",
 
 	RunE: func(cmd *cobra.Command, args []string) error {
-		codeDir, err := util.CodeDirName()
+		workDir, err := projectRoot()
 		if err != nil {
-			fmt.Printf("failed to get code directory: %w\n", err)
-			return nil
-		}
-		workDir, err := os.Getwd()
-		if err != nil {
-			if err != nil {
-				fmt.Printf("failed to get pwd: %w\n", err)
-				return nil
-			}
-		}
-
-		if filepath.Dir(codeDir) != workDir {
-			fmt.Printf("cannot generate code unless in the source directory\n")
+			fmt.Println(err.Error())
 			return nil
 		}
 
-		if deleteArgs.Year < 2015 {
-			return fmt.Errorf("must specify a year on or after 2015")
+		if deleteArgs.Day == 0 {
+			err = calendar.ValidateYear(deleteArgs.Year)
+		} else {
+			err = calendar.ValidateDay(deleteArgs.Day, deleteArgs.Year)
 		}
-
-		if deleteArgs.Day > 25 {
-			return fmt.Errorf("must specify a day before Dec. 25th")
+		if err != nil {
+			return err
 		}
 
 		solutionsPackage := filepath.Join(workDir, "solutions/solution_code")
@@ -53,26 +68,53 @@ var deleteCMD = &cobra.Command{
 			rmTarget = filepath.Join(rmTarget, fmt.Sprintf("day%d", deleteArgs.Day))
 		}
 
-		err = os.RemoveAll(rmTarget)
+		registered := util.Ternary(deleteArgs.Day == 0,
+			solutions.Index.HasYear(deleteArgs.Year),
+			solutions.Index.Has(deleteArgs.Day, deleteArgs.Year),
+		)
+		if _, statErr := os.Stat(rmTarget); !registered && os.IsNotExist(statErr) {
+			return fmt.Errorf("nothing to delete: %s is not registered and has no code", rmTarget)
+		}
+
+		tx, err := beginTransaction(util.Ternary(deleteArgs.Day == 0,
+			fmt.Sprintf("delete %d", deleteArgs.Year),
+			fmt.Sprintf("delete %d/%d", deleteArgs.Year, deleteArgs.Day),
+		))
 		if err != nil {
-			fmt.Printf("Failed to remove directory %s: %w", rmTarget, err)
-			return nil
+			return err
+		}
+
+		err = deleteDay(tx, workDir, rmTarget)
+		if err == nil && !deleteArgs.NoVerify {
+			err = solution_templates.VerifyBuild(workDir)
 		}
 
-		// Generate the importer code
-		err = solution_templates.UpdateImporter()
 		if err != nil {
-			fmt.Printf("failed to update importer: %s\n", err.Error())
+			fmt.Println(err.Error())
+			if rbErr := tx.Rollback(); rbErr != nil {
+				fmt.Printf("rollback failed: %s\n", rbErr.Error())
+			} else {
+				fmt.Println("rolled back; nothing was deleted")
+			}
 			return nil
 		}
 
-		err = inputs.Cache.DeleteInput(deleteArgs.Day, deleteArgs.Year)
+		cached, err := inputs.Cache.CachedPaths(deleteArgs.Day, deleteArgs.Year)
 		if err != nil {
-			fmt.Printf("failed to delete inputs: %s", err.Error())
-			return nil
+			return err
 		}
 
-		return nil
+		for _, path := range cached {
+			if _, err := os.Stat(path); os.IsNotExist(err) {
+				continue // nothing to keep, and undo shouldn't remove whatever is cached there later
+			}
+
+			if err := tx.Remove(path); err != nil {
+				fmt.Printf("failed to delete inputs: %s\n", err.Error())
+			}
+		}
+
+		return tx.Commit()
 	},
 }
 
@@ -80,5 +122,7 @@ func init() {
 	deleteCMD.PersistentFlags().UintVar(&deleteArgs.Year, "year", 0, "Year to remove (from). If used alone, removes the entire year. Must be set.")
 	deleteCMD.PersistentFlags().UintVar(&deleteArgs.Day, "day", 0, "Day to remove from the target year. Optional.")
 
+	deleteCMD.PersistentFlags().BoolVar(&deleteArgs.NoVerify, "no-verify", false, "Skip building the solution code after deleting. (default: false)")
+
 	RootCmd.AddCommand(deleteCMD)
 }
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions/solution_templates"
+	"github.com/spf13/cobra"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var dayDirRegex = regexp.MustCompile(`^(\d+)/day(\d+)$`)
+
+// doctorLayout compares the solution_code/<year>/day<num> directories against what is registered in the index.
+// Shared packages, solution_code/<year>/shared/<name>, are expected to register nothing.
+func doctorLayout(workDir string) (problems []string, err error) {
+	solutionsPackage := filepath.Join(workDir, filepath.FromSlash(core.SolutionCodeDir))
+
+	codePath, err := core.SolutionCodePathOf(workDir)
+	if err != nil {
+		return nil, err
+	}
+
+	importer, err := os.ReadFile(filepath.Join(solutionsPackage, "importer.go"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read importer: %w", err)
+	}
+
+	// every registered package, so directories can be matched against them
+	registered := map[string]bool{}
+	for _, entry := range solutions.Index.All() {
+		for _, variant := range solutions.Index.Variants(entry.Number, entry.Year) {
+			registered[solutions.Index.Source(entry.Number, entry.Year, variant)] = true
+		}
+	}
+
+	seenDirs := map[string]bool{}
+	err = filepath.WalkDir(solutionsPackage, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".go") || strings.HasSuffix(d.Name(), "_test.go") {
+			return nil
+		}
+
+		dir, _ := filepath.Rel(solutionsPackage, filepath.Dir(path))
+		dir = filepath.ToSlash(dir)
+		if dir == "." || seenDirs[dir] {
+			return nil
+		}
+		seenDirs[dir] = true
+
+		pkg := codePath + "/" + dir
+		if solution_templates.IsSharedPackage(dir) {
+			if registered[pkg] {
+				problems = append(problems, fmt.Sprintf("%s is a shared package, but registers days; the importer won't import it", dir))
+			}
+			return nil
+		}
+
+		match := dayDirRegex.FindStringSubmatch(dir)
+		if match == nil {
+			problems = append(problems, fmt.Sprintf("%s does not follow the <year>/day<num> or <year>/shared/<name> layout", dir))
+			return nil
+		}
+
+		year, _ := strconv.ParseUint(match[1], 10, 64)
+		day, _ := strconv.ParseUint(match[2], 10, 64)
+		switch {
+		case !strings.Contains(string(importer), `"`+pkg+`"`):
+			problems = append(problems, fmt.Sprintf("%s is not imported by importer.go; run `aocf create` or `aocf delete` to regenerate it", dir))
+		case !registered[pkg]:
+			problems = append(problems, fmt.Sprintf("%s is imported, but registers nothing (or aocf needs rebuilding)", dir))
+		case !solutions.Index.Has(uint(day), uint(year)):
+			problems = append(problems, fmt.Sprintf("%s registers days, but not day %d/%d", dir, year, day))
+		}
+
+		return nil
+	})
+
+	// registered days whose code is gone mean the binary is stale.
+	stale := make([]string, 0)
+	for pkg := range registered {
+		dir := strings.TrimPrefix(pkg, codePath+"/")
+		if dir != pkg && !seenDirs[dir] {
+			stale = append(stale, fmt.Sprintf("%s is registered, but its code is gone; rebuild aocf", dir))
+		}
+	}
+	sort.Strings(stale)
+
+	return append(problems, stale...), err
+}
+
+var doctorCommand = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the solution index for registration problems, and against the solution_code/<year>/day<num> layout.",
+
+	// doctor reports registration errors itself.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		problems := make([]string, 0)
+		for _, err := range solutions.Index.RegistrationErrors() {
+			problems = append(problems, err.Error())
+		}
+
+		workDir, err := projectRoot()
+		if err != nil {
+			fmt.Printf("Skipping layout checks: %s\n", err.Error())
+		} else {
+			layoutProblems, err := doctorLayout(workDir)
+			if err != nil {
+				fmt.Printf("Failed to check layout: %s\n", err.Error())
+			}
+			problems = append(problems, layoutProblems...)
+		}
+
+		for _, year := range solutions.Index.Years() {
+			if gaps := solutions.Index.Gaps(year); len(gaps) != 0 {
+				fmt.Printf("Note: %d skips days %v\n", year, gaps)
+			}
+		}
+
+		if len(problems) == 0 {
+			fmt.Println("No problems found.")
+			return nil
+		}
+
+		fmt.Printf("Found %d problem(s):\n", len(problems))
+		for _, p := range problems {
+			fmt.Printf("  %s\n", p)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(doctorCommand)
+}
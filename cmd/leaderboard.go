@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/calendar"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
+	"github.com/Riven-Spell/advent_of_code_forever/leaderboard"
+	"github.com/Riven-Spell/advent_of_code_forever/util"
+	"github.com/spf13/cobra"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+var leaderboardArgs = struct {
+	ID   uint
+	Year uint
+}{}
+
+// leaderboardTarget resolves the leaderboard ID & year from flags, falling back to the environment and latest event.
+func leaderboardTarget() (id, year uint, err error) {
+	id, year = leaderboardArgs.ID, leaderboardArgs.Year
+
+	if id == 0 {
+		envID, def := core.EEnvironmentVariable.LeaderboardID().Get()
+		if def {
+			return 0, 0, fmt.Errorf("must specify --id or set %s", core.EEnvironmentVariable.LeaderboardID().Name)
+		}
+
+		parsed, err := strconv.ParseUint(envID, 10, 64)
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid leaderboard ID '%s': %w", envID, err)
+		}
+		id = uint(parsed)
+	}
+
+	if year == 0 {
+		_, year = calendar.LatestUnlocked(core.SystemClock.Now())
+	}
+
+	return id, year, nil
+}
+
+func formatDelta(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%dd%s", d/(24*time.Hour), (d % (24 * time.Hour)).String())
+	}
+
+	return d.String()
+}
+
+func printLeaderboard(board *leaderboard.Leaderboard) {
+	days := board.Days()
+	members := board.Ranked()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+
+	header := []string{"RANK", "MEMBER", "SCORE", "STARS"}
+	for d := uint(1); d <= days; d++ {
+		header = append(header, fmt.Sprint(d))
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	for rank, m := range members {
+		row := []string{fmt.Sprint(rank + 1), m.DisplayName(), fmt.Sprint(m.LocalScore), fmt.Sprint(m.Stars)}
+		for d := uint(1); d <= days; d++ {
+			row = append(row, []string{".", "+", "*"}[m.StarCount(d)])
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	_ = w.Flush()
+
+	fmt.Println()
+	fmt.Println("Part 1 -> Part 2 deltas:")
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	_, _ = fmt.Fprintln(w, strings.Join(append([]string{"MEMBER"}, header[4:]...), "\t"))
+	for _, m := range members {
+		row := []string{m.DisplayName()}
+		for d := uint(1); d <= days; d++ {
+			delta, ok := m.Delta(d)
+			row = append(row, util.Ternary(ok, formatDelta(delta), "-"))
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	_ = w.Flush()
+}
+
+var leaderboardCommand = &cobra.Command{
+	Use:   "leaderboard [--id <id>] [--year <year>]",
+	Short: "View a private leaderboard. Refreshes at most every 15 minutes, as requested by AoC.",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, year, err := leaderboardTarget()
+		if err != nil {
+			return err
+		}
+
+		board, err := leaderboard.Default.Get(id, year)
+		if err != nil {
+			fmt.Printf("Failed to get leaderboard %d for %d: %s\n", id, year, err.Error())
+			return nil
+		}
+
+		printLeaderboard(board)
+
+		return nil
+	},
+}
+
+func init() {
+	leaderboardCommand.PersistentFlags().UintVar(&leaderboardArgs.ID, "id", 0, "Private leaderboard ID. Defaults to AOCF_LEADERBOARD_ID.")
+	leaderboardCommand.PersistentFlags().UintVar(&leaderboardArgs.Year, "year", 0, "Event year. Current year assumed if not specified.")
+
+	RootCmd.AddCommand(leaderboardCommand)
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/leaderboard"
+	"github.com/Riven-Spell/advent_of_code_forever/util"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+var leaderboardStatsArgs = struct {
+	Scheme     string
+	IgnoreDays []uint
+	Format     string
+}{}
+
+func printLeaderboardStats(stats leaderboard.Stats) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	_, _ = fmt.Fprintln(w, "RANK\tMEMBER\tSCORE ("+stats.Scheme+")\tSTARS\tFIRST\tMEDIAN SOLVE\tMEDIAN DELTA")
+	for _, m := range stats.Members {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%g\t%d\t%d\t%s\t%s\n",
+			m.Rank, m.Name, m.Score, m.Stars, m.FirstSolves,
+			util.Ternary(m.MedianSolve != 0, formatDelta(m.MedianSolve), "-"),
+			util.Ternary(m.MedianDelta != 0, formatDelta(m.MedianDelta), "-"),
+		)
+	}
+	_ = w.Flush()
+
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 0, 1, ' ', 0)
+	_, _ = fmt.Fprintln(w, "DAY\tFIRST PART 1\tFIRST PART 2\tMEDIAN DELTA")
+	for _, d := range stats.Days {
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n",
+			d.Day,
+			util.Ternary(d.FirstPart1 != "", d.FirstPart1, "-"),
+			util.Ternary(d.FirstPart2 != "", d.FirstPart2, "-"),
+			util.Ternary(d.MedianDelta != 0, formatDelta(d.MedianDelta), "-"),
+		)
+	}
+	_ = w.Flush()
+}
+
+var leaderboardStatsCommand = &cobra.Command{
+	Use:   "stats [--id <id>] [--year <year>] [--scheme local|time|stars] [--ignore-days 1,2] [--format table|json]",
+	Short: "Star-time analytics for a private leaderboard. Runs entirely from the cached leaderboard.",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, year, err := leaderboardTarget()
+		if err != nil {
+			return err
+		}
+
+		scheme, ok := leaderboard.ScoringSchemeByName(strings.ToLower(leaderboardStatsArgs.Scheme))
+		if !ok {
+			return fmt.Errorf("unknown scoring scheme '%s'", leaderboardStatsArgs.Scheme)
+		}
+
+		board, err := leaderboard.Default.GetCached(id, year)
+		if err != nil {
+			fmt.Printf("No cached leaderboard %d for %d, run `aocf leaderboard` first: %s\n", id, year, err.Error())
+			return nil
+		}
+
+		stats := board.Stats(scheme, leaderboardStatsArgs.IgnoreDays)
+
+		switch strings.ToLower(leaderboardStatsArgs.Format) {
+		case "table":
+			printLeaderboardStats(stats)
+		case "json":
+			buf, err := json.MarshalIndent(stats, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(buf))
+		default:
+			return fmt.Errorf("unknown format '%s'", leaderboardStatsArgs.Format)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	leaderboardStatsCommand.PersistentFlags().StringVar(&leaderboardStatsArgs.Scheme, "scheme", "local", "Scoring scheme: local (AoC's), time (time since unlock), or stars.")
+	leaderboardStatsCommand.PersistentFlags().UintSliceVar(&leaderboardStatsArgs.IgnoreDays, "ignore-days", nil, "Days to leave out of every statistic, e.g. 1,6")
+	leaderboardStatsCommand.PersistentFlags().StringVar(&leaderboardStatsArgs.Format, "format", "table", "Output format: table or json.")
+
+	leaderboardCommand.AddCommand(leaderboardStatsCommand)
+}
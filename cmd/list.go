@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/calendar"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+var listArgs = struct {
+	Year   uint
+	Tag    string
+	Status string // all, implemented, missing
+	Format string
+}{}
+
+type listEntry struct {
+	Year     uint                `json:"year"`
+	Day      uint                `json:"day"`
+	Status   string              `json:"status"`
+	Variants []string            `json:"variants,omitempty"`
+	Meta     *solutions.Metadata `json:"meta,omitempty"`
+}
+
+// listEntries walks every unlocked day of every year up to the latest registered one, applying the filters.
+func listEntries() []listEntry {
+	latestDay, latestYear := calendar.LatestUnlocked(core.SystemClock.Now())
+
+	years := solutions.Index.Years()
+	if len(years) == 0 {
+		return []listEntry{}
+	}
+
+	out := make([]listEntry, 0)
+	for year := uint(calendar.FirstYear); year <= years[len(years)-1]; year++ {
+		if listArgs.Year != 0 && year != listArgs.Year {
+			continue
+		}
+
+		for d := uint(1); d <= calendar.DaysIn(year); d++ {
+			day := solutions.Index.Get(d, year)
+			if day == nil && (year > latestYear || (year == latestYear && d > latestDay)) {
+				continue // not out yet, so it can't be missing
+			}
+
+			entry := listEntry{Year: year, Day: d, Status: "missing"}
+			if day != nil {
+				entry.Status = "implemented"
+				entry.Variants = solutions.Index.Variants(d, year)
+				entry.Meta = day.Meta
+			}
+
+			if listArgs.Status != "all" && listArgs.Status != entry.Status {
+				continue
+			}
+
+			if listArgs.Tag != "" && !entry.Meta.HasTag(listArgs.Tag) {
+				continue
+			}
+
+			out = append(out, entry)
+		}
+	}
+
+	return out
+}
+
+var listCommand = &cobra.Command{
+	Use:   "list [--year <year>] [--tag <tag>] [--status all|implemented|missing] [--format table|json]",
+	Short: "Browse the days in the solution index, with their metadata.",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listArgs.Status = strings.ToLower(listArgs.Status)
+		switch listArgs.Status {
+		case "all", "implemented", "missing":
+		default:
+			return fmt.Errorf("unknown status '%s'", listArgs.Status)
+		}
+
+		entries := listEntries()
+
+		switch strings.ToLower(listArgs.Format) {
+		case "table":
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "YEAR\tDAY\tSTATUS\tTITLE\tTAGS\tCOMPLEXITY\tAUTHOR\tVARIANTS")
+			for _, e := range entries {
+				meta := e.Meta
+				if meta == nil {
+					meta = &solutions.Metadata{}
+				}
+
+				_, _ = fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+					e.Year, e.Day, e.Status, meta.Title, strings.Join(meta.Tags, ","),
+					meta.ComplexityClass, meta.Author, strings.Join(e.Variants, ","),
+				)
+			}
+			_ = w.Flush()
+		case "json":
+			buf, err := json.MarshalIndent(entries, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(buf))
+		default:
+			return fmt.Errorf("unknown format '%s'", listArgs.Format)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	listCommand.PersistentFlags().UintVar(&listArgs.Year, "year", 0, "Only list this year.")
+	listCommand.PersistentFlags().StringVar(&listArgs.Tag, "tag", "", "Only list days with this tag, e.g. graph, dp, grid.")
+	listCommand.PersistentFlags().StringVar(&listArgs.Status, "status", "implemented", "all, implemented, or missing (released days with no code).")
+	listCommand.PersistentFlags().StringVar(&listArgs.Format, "format", "table", "Output format: table or json.")
+
+	RootCmd.AddCommand(listCommand)
+}
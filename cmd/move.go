@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/calendar"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
+	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions/solution_templates"
+	"github.com/Riven-Spell/advent_of_code_forever/transaction"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var moveArgs = struct {
+	From     string
+	To       string
+	NoVerify bool
+}{}
+
+// parseDayRef parses a "<year>/<day>" reference to a day.
+func parseDayRef(ref string) (day, year uint, err error) {
+	yearStr, dayStr, ok := strings.Cut(strings.TrimSpace(ref), "/")
+	if !ok {
+		return 0, 0, fmt.Errorf("'%s' is not of the form <year>/<day>", ref)
+	}
+
+	y, err := strconv.ParseUint(yearStr, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("'%s' has no valid year: %w", ref, err)
+	}
+
+	d, err := strconv.ParseUint(dayStr, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("'%s' has no valid day: %w", ref, err)
+	}
+
+	return uint(d), uint(y), calendar.ValidateDay(uint(d), uint(y))
+}
+
+// moveDay moves a day's package and rewrites its source and importers, tracking everything in tx.
+func moveDay(tx *transaction.Transaction, workDir string, m solution_templates.DayMove) error {
+	solutionsPackage := filepath.Join(workDir, "solutions/solution_code")
+	fromDir := filepath.Join(solutionsPackage, filepath.FromSlash(m.FromPackage()))
+	toDir := filepath.Join(solutionsPackage, filepath.FromSlash(m.ToPackage()))
+
+	importers, err := solution_templates.FilesAffectedByMove(solutionsPackage, m)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range append([]string{fromDir, toDir, filepath.Join(solutionsPackage, "importer.go")}, importers...) {
+		if err := tx.Track(path); err != nil {
+			return err
+		}
+	}
+
+	err = os.MkdirAll(filepath.Dir(toDir), 0755)
+	if err != nil {
+		return fmt.Errorf("cannot create folders: %w", err)
+	}
+
+	err = os.Rename(fromDir, toDir)
+	if err != nil {
+		return fmt.Errorf("cannot move %s: %w", fromDir, err)
+	}
+
+	// a year left with no days or shared packages goes with its last day.
+	yearDir := filepath.Dir(fromDir)
+	if entries, err := os.ReadDir(yearDir); err == nil && len(entries) == 0 {
+		if err := tx.Remove(yearDir); err != nil {
+			return err
+		}
+	}
+
+	err = solution_templates.RewriteMovedDay(toDir, importers, m)
+	if err != nil {
+		return err
+	}
+
+	// Generate the importer code
+	err = solution_templates.UpdateImporter(workDir)
+	if err != nil {
+		return fmt.Errorf("failed to update importer: %w", err)
+	}
+
+	return nil
+}
+
+// moveCached moves a day's cached input, solution and puzzle, tracking them in tx.
+func moveCached(tx *transaction.Transaction, m solution_templates.DayMove) error {
+	from, err := inputs.Cache.CachedPaths(m.FromDay, m.FromYear)
+	if err != nil {
+		return err
+	}
+
+	to, err := inputs.Cache.CachedPaths(m.ToDay, m.ToYear)
+	if err != nil {
+		return err
+	}
+
+	for i := range from {
+		if _, err := os.Stat(from[i]); os.IsNotExist(err) {
+			continue
+		}
+
+		for _, path := range []string{from[i], to[i]} {
+			if err := tx.Track(path); err != nil {
+				return err
+			}
+		}
+
+		err = os.MkdirAll(filepath.Dir(to[i]), 0755)
+		if err == nil {
+			err = os.Rename(from[i], to[i])
+		}
+		if err != nil {
+			return fmt.Errorf("cannot move %s: %w", from[i], err)
+		}
+	}
+
+	return nil
+}
+
+var moveCommand = &cobra.Command{
+	Use:   "move --from <year>/<day> --to <year>/<day>",
+	Short: "Move a day's code and cached inputs to another day or year.",
+	Long:  "Moves a day's package, renaming its package clause, Day<N> identifiers and registration, then moves its cached inputs and solutions and regenerates the importer. Can be reverted with `aocf undo`.",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		workDir, err := projectRoot()
+		if err != nil {
+			fmt.Println(err.Error())
+			return nil
+		}
+
+		var m solution_templates.DayMove
+		m.FromDay, m.FromYear, err = parseDayRef(moveArgs.From)
+		if err != nil {
+			return fmt.Errorf("invalid --from: %w", err)
+		}
+
+		m.ToDay, m.ToYear, err = parseDayRef(moveArgs.To)
+		if err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+
+		m.CodePath, err = core.SolutionCodePathOf(workDir)
+		if err != nil {
+			return err
+		}
+
+		solutionsPackage := filepath.Join(workDir, "solutions/solution_code")
+		if _, err := os.Stat(filepath.Join(solutionsPackage, filepath.FromSlash(m.FromPackage()))); err != nil {
+			return fmt.Errorf("nothing to move: %s has no code", m.FromPackage())
+		}
+
+		// check we're not overwriting anything
+		if _, err := os.Stat(filepath.Join(solutionsPackage, filepath.FromSlash(m.ToPackage()))); err == nil || solutions.Index.Has(m.ToDay, m.ToYear) {
+			return fmt.Errorf("not moving onto %s, as it already exists", m.ToPackage())
+		}
+
+		if inputs.Cache.HasCachedInput(m.ToDay, m.ToYear) {
+			return fmt.Errorf("not moving onto %s, as it already has a cached input", m.ToPackage())
+		}
+
+		tx, err := beginTransaction(fmt.Sprintf("move %d/%d to %d/%d", m.FromYear, m.FromDay, m.ToYear, m.ToDay))
+		if err != nil {
+			return err
+		}
+
+		err = moveDay(tx, workDir, m)
+		if err == nil && !moveArgs.NoVerify {
+			err = solution_templates.VerifyBuild(workDir)
+		}
+		if err == nil {
+			err = moveCached(tx, m)
+		}
+
+		if err != nil {
+			fmt.Println(err.Error())
+			if rbErr := tx.Rollback(); rbErr != nil {
+				fmt.Printf("rollback failed: %s\n", rbErr.Error())
+			} else {
+				fmt.Println("rolled back; nothing was moved")
+			}
+			return nil
+		}
+
+		return tx.Commit()
+	},
+}
+
+func init() {
+	moveCommand.PersistentFlags().StringVar(&moveArgs.From, "from", "", "Day to move, as <year>/<day>. Must be set.")
+	moveCommand.PersistentFlags().StringVar(&moveArgs.To, "to", "", "Where to move it, as <year>/<day>. Must be set.")
+	moveCommand.PersistentFlags().BoolVar(&moveArgs.NoVerify, "no-verify", false, "Skip building the solution code after moving. (default: false)")
+
+	RootCmd.AddCommand(moveCommand)
+}
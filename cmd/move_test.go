@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"github.com/Riven-Spell/advent_of_code_forever/solutions/solution_templates"
+	"github.com/Riven-Spell/advent_of_code_forever/transaction"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestProject lays out a solutions repository holding the given days, each rendered from the default template.
+func newTestProject(t *testing.T, days ...solution_templates.DayMove) string {
+	t.Helper()
+
+	root := t.TempDir()
+	solutionsPackage := filepath.Join(root, "solutions/solution_code")
+	if err := os.MkdirAll(solutionsPackage, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "go.mod"), []byte("module example.com/solutions\n\ngo 1.19\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := solution_templates.LoadSolutionTemplate(root, solution_templates.DefaultSolutionTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, d := range days {
+		infill := solution_templates.SolutionTemplateInfill{Package: filepath.Base(d.FromPackage()), Day: d.FromDay, Year: d.FromYear}
+		code, err := solution_templates.RenderSolution(tmpl, infill)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dir := filepath.Join(solutionsPackage, filepath.FromSlash(d.FromPackage()))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, infill.Package+".go"), code, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := solution_templates.UpdateImporter(root); err != nil {
+		t.Fatal(err)
+	}
+
+	return root
+}
+
+func TestMoveDayRemovesEmptyYear(t *testing.T) {
+	last := solution_templates.DayMove{FromDay: 1, FromYear: 2022, ToDay: 2, ToYear: 2023, CodePath: "example.com/solutions/solutions/solution_code"}
+	root := newTestProject(t, last)
+	yearDir := filepath.Join(root, "solutions/solution_code/2022")
+
+	trash := t.TempDir()
+	tx, err := transaction.Begin(trash, "move")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := moveDay(tx, root, last); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(yearDir); !os.IsNotExist(err) {
+		t.Errorf("2022 should be removed with its last day: %v", err)
+	}
+
+	importer, err := os.ReadFile(filepath.Join(root, "solutions/solution_code/importer.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(importer), `"example.com/solutions/solutions/solution_code/2023/day2"`) {
+		t.Errorf("importer does not import the moved day:\n%s", importer)
+	}
+
+	// undoing the move brings the year back, along with its day.
+	if _, err := transaction.Undo(trash, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(yearDir, "day1", "day1.go")); err != nil {
+		t.Errorf("undo did not restore 2022/day1: %v", err)
+	}
+}
+
+func TestMoveDayKeepsOccupiedYear(t *testing.T) {
+	moved := solution_templates.DayMove{FromDay: 1, FromYear: 2022, ToDay: 2, ToYear: 2023, CodePath: "example.com/solutions/solutions/solution_code"}
+	other := solution_templates.DayMove{FromDay: 3, FromYear: 2022}
+	root := newTestProject(t, moved, other)
+
+	tx, err := transaction.Begin(t.TempDir(), "move")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := moveDay(tx, root, moved); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(root, "solutions/solution_code/2022/day3")); err != nil {
+		t.Errorf("2022 still has day 3, and should be kept: %v", err)
+	}
+}
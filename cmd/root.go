@@ -1,9 +1,68 @@
 package cmd
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+)
+
+var rootArgs = struct {
+	Root string
+}{}
+
+// projectRoot finds the root of the solutions repository (advent_of_code_forever or any module laid out like it):
+// --root, then $AOCF_ROOT, then the nearest parent of the working directory holding a go.mod and solutions/solution_code.
+func projectRoot() (string, error) {
+	root := rootArgs.Root
+	if root == "" {
+		root, _ = core.EEnvironmentVariable.ProjectRoot().Get()
+	}
+
+	if root != "" {
+		if !core.IsProjectRoot(root) {
+			return "", fmt.Errorf("%s is not the root of a solutions repository (no go.mod, or no solutions/solution_code)", root)
+		}
+
+		return filepath.Abs(root)
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return "", fmt.Errorf("failed to get pwd: %w", err)
+	}
+
+	root, err = core.FindProjectRoot(workDir)
+	if err != nil {
+		return "", fmt.Errorf("cannot find the source directory (try --root or $AOCF_ROOT): %w", err)
+	}
+
+	return root, nil
+}
 
 var RootCmd = &cobra.Command{
 	Use:   "aocf",
 	Short: "Advent of Code Forever",
 	Long:  "Advent of Code codebase for the long-term.",
+
+	// Registration happens in init(), before any command runs, so problems are reported here.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		errs := solutions.Index.RegistrationErrors()
+		if len(errs) == 0 {
+			return
+		}
+
+		_, _ = fmt.Fprintf(os.Stderr, "WARNING: %d day(s) failed to register, and were skipped:\n", len(errs))
+		for _, err := range errs {
+			_, _ = fmt.Fprintf(os.Stderr, "  %s\n", err.Error())
+		}
+		_, _ = fmt.Fprintln(os.Stderr, "Run `aocf doctor` for more detail.")
+		_, _ = fmt.Fprintln(os.Stderr)
+	},
+}
+
+func init() {
+	RootCmd.PersistentFlags().StringVar(&rootArgs.Root, "root", "", "Root of the solutions repository (advent_of_code_forever or a module laid out like it). Found from the working directory if not specified.")
 }
@@ -1,13 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/calendar"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
 	"github.com/Riven-Spell/advent_of_code_forever/inputs"
 	"github.com/Riven-Spell/advent_of_code_forever/solutions"
 	"github.com/Riven-Spell/advent_of_code_forever/util"
 	"github.com/spf13/cobra"
-	"reflect"
+	"os"
+	"os/signal"
 	"strings"
+	"text/tabwriter"
 	"time"
 )
 
@@ -18,47 +23,70 @@ var runArgs = struct {
 	CacheAnswers    bool
 	InputMode       string // cache, download, generate
 	InputComplexity uint64
+	Timeout         time.Duration
+	Variant         string // a variant name, or all
+	Plugin          bool
 }{}
 
-func runDay(cDay, cYear uint) error {
-	day := solutions.Index.Get(cDay, cYear)
-	if day == nil {
-		return fmt.Errorf("day %d/%d is not available")
+func runDay(ctx context.Context, cDay, cYear uint) error {
+	if err := calendar.ValidateDay(cDay, cYear); err != nil {
+		return err
 	}
 
-	var input string
+	runner, err := openDayRunner(cDay, cYear)
+	if err != nil {
+		return err
+	}
+	defer runner.close()
+
+	var inputPath string
 	var solution *inputs.Solution
-	var err error
 
 	inputMode := strings.ToLower(runArgs.InputMode)
 	switch inputMode {
 	case "download":
-		err = inputs.Cache.DownloadInput(cDay, cYear, true)
+		err = calendar.ValidateUnlocked(cDay, cYear, core.SystemClock.Now())
+		if err == nil {
+			err = inputs.Cache.DownloadInput(cDay, cYear, true)
+		}
 		if err != nil {
 			fmt.Printf("Day %d/%d: Failed to download input: %s\n", cYear, cDay, err.Error())
 			return nil
 		}
 		fallthrough
 	case "cache":
-		input, solution, err = inputs.Cache.GetInputAndSolution(cDay, cYear)
-		if err != nil {
-			fmt.Printf("Day %d/%d: Failed to pull input from cache: %s\n", cYear, cDay, err.Error())
+		if !inputs.Cache.HasCachedInput(cDay, cYear) {
+			fmt.Printf("Day %d/%d: Failed to pull input from cache: no input cached\n", cYear, cDay)
 			return nil
 		}
+
+		cached, err := inputs.Cache.CachedPaths(cDay, cYear)
+		if err != nil {
+			return err
+		}
+		inputPath = cached[0]
+		solution, _ = inputs.Cache.GetSolution(cDay, cYear)
 	case "generate":
-		if day.Generator == nil {
+		if !runner.hasGenerator() {
 			return fmt.Errorf("day %d/%d does not contain an input generator", cYear, cDay)
 		}
 
-		complexity := runArgs.InputComplexity
-		if complexity == 0 {
-			complexity = day.DefaultComplexity
+		// generate to a scratch file rather than memory, then stream it back for each part.
+		f, err := os.CreateTemp("", fmt.Sprintf("aocf-%d-%d-*.txt", cYear, cDay))
+		if err != nil {
+			return err
 		}
+		_ = f.Close()
+		defer os.Remove(f.Name())
 
-		input, solution = day.Generator(complexity)
+		solution, err = runner.generate(ctx, runArgs.InputComplexity, f.Name())
+		if err != nil {
+			fmt.Printf("Day %d/%d: Failed to generate input: %s\n", cYear, cDay, err.Error())
+			return nil
+		}
+		inputPath = f.Name()
 	}
 
-	runner := day.Solution
 	if solution == nil {
 		solution = &inputs.Solution{}
 	}
@@ -68,67 +96,166 @@ func runDay(cDay, cYear uint) error {
 			return ""
 		}
 
-		if reflect.DeepEqual(result, expected) {
+		if inputs.AnswersEqual(result, expected) {
 			return " (PASSED)"
 		} else {
 			return " (FAILED: expected " + fmt.Sprint(expected) + ")"
 		}
 	}
 
-	if runArgs.Part == -1 || runArgs.Part == 1 {
-		// Part 1
-		runner.Prepare(input)
-		startTime := time.Now() // time the run
-		result := runner.Part1()
-		if result != nil {
-			endTime := time.Now().Sub(startTime)
-			fmt.Printf("PART 1: %v%s in %s\n", result, getResult(result, solution.A), endTime.String())
+	printResult := func(prefix string, r solutions.PartResult, expected any) {
+		switch r.Status {
+		case solutions.ERunStatus.Answered():
+			fmt.Printf("%sPART %d: %v%s in %s%s\n", prefix, r.Part, r.Answer, getResult(r.Answer, expected), r.Duration.String(),
+				util.Ternary(r.Prepare != 0, " (prepared in "+r.Prepare.String()+")", ""))
+		case solutions.ERunStatus.NotImplemented():
+			fmt.Printf("%sPART %d: not implemented\n", prefix, r.Part)
+		case solutions.ERunStatus.Cancelled():
+			fmt.Printf("%sPART %d: cancelled (%s)\n", prefix, r.Part, r.Err.Error())
+		default:
+			fmt.Printf("%sPART %d: failed: %s\n", prefix, r.Part, r.Err.Error())
 		}
 	}
 
-	if runArgs.Part == -1 || runArgs.Part == 2 {
-		// Part 1
-		runner.Prepare(input)
-		startTime := time.Now() // time the run
-		result := runner.Part2()
-		if result != nil {
-			endTime := time.Now().Sub(startTime)
-			fmt.Printf("PART 2: %v%s in %s\n", result, getResult(result, solution.B), endTime.String())
+	variants, err := selectVariants(runner, cDay, cYear)
+	if err != nil {
+		return err
+	}
+
+	parts := make([]int, 0, 2)
+	if runArgs.Part == -1 || runArgs.Part == 1 {
+		parts = append(parts, 1)
+	}
+
+	if runArgs.Part == 2 && calendar.Parts(cDay, cYear) < 2 {
+		fmt.Printf("PART 2: day %d/%d is the final day, and has no second part\n", cYear, cDay)
+	}
+
+	if (runArgs.Part == -1 || runArgs.Part == 2) && calendar.Parts(cDay, cYear) >= 2 {
+		parts = append(parts, 2)
+	}
+
+	results := make([][]solutions.PartResult, len(variants))
+	for v, variant := range variants {
+		prefix := util.Ternary(len(variants) > 1, "["+variant+"] ", "")
+		dayResult := runner.run(ctx, variant, inputPath, parts)
+		if dayResult.PreparedOnce {
+			fmt.Printf("%sPREPARE: %s (shared by both parts)\n", prefix, dayResult.Prepare.String())
 		}
+
+		for _, r := range dayResult.Parts {
+			printResult(prefix, r, util.Ternary(r.Part == 1, solution.A, solution.B))
+		}
+		results[v] = dayResult.Parts
+	}
+
+	if len(variants) > 1 {
+		printVariantComparison(variants, parts, results)
 	}
 
 	return nil
 }
 
+// selectVariants resolves --variant into the variant names to run.
+func selectVariants(runner dayRunner, cDay, cYear uint) ([]string, error) {
+	all, def := runner.variants()
+
+	switch runArgs.Variant {
+	case "all":
+		return all, nil
+	case "":
+		if def == "" {
+			return nil, fmt.Errorf("day %d/%d is not available", cYear, cDay)
+		}
+		return []string{def}, nil
+	default:
+		for _, v := range all {
+			if v == runArgs.Variant {
+				return []string{v}, nil
+			}
+		}
+		return nil, fmt.Errorf("day %d/%d has no variant '%s' (available: %s)",
+			cYear, cDay, runArgs.Variant, strings.Join(all, ", "))
+	}
+}
+
+// printVariantComparison cross-checks that every variant agrees on its answers, then tabulates their timings.
+func printVariantComparison(variants []string, parts []int, results [][]solutions.PartResult) {
+	for p, part := range parts {
+		var reference *solutions.PartResult
+		var referenceName string
+
+		for v := range variants {
+			r := &results[v][p]
+			if r.Status != solutions.ERunStatus.Answered() {
+				continue
+			}
+
+			if reference == nil {
+				reference, referenceName = r, variants[v]
+			} else if !inputs.AnswersEqual(r.Answer, reference.Answer) {
+				fmt.Printf("PART %d: MISMATCH: %s answered %v, but %s answered %v\n",
+					part, variants[v], r.Answer, referenceName, reference.Answer)
+			}
+		}
+	}
+
+	fmt.Println()
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	header := "VARIANT"
+	for _, part := range parts {
+		header += fmt.Sprintf("\tPART %d", part)
+	}
+	_, _ = fmt.Fprintln(w, header)
+
+	for v, variant := range variants {
+		row := variant
+		for p := range parts {
+			r := results[v][p]
+			row += "\t" + util.Ternary(r.Status == solutions.ERunStatus.Answered(), r.Duration.String(), r.Status.String())
+		}
+		_, _ = fmt.Fprintln(w, row)
+	}
+	_ = w.Flush()
+}
+
 var runCommand = &cobra.Command{
 	Use:   "run [--year <year> --day <day> | --all] [--part <1/2>]",
 	Short: "Runs a day with it's input. Can generate or download input on the fly. If no year/day is specified, both parts of the most recent day will be ran if available.",
 
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if runArgs.All {
-			_, maxYear := solutions.Index.GetCurrentDay()
-			if runArgs.Year != 0 {
-				maxYear = runArgs.Year
+		days := solutions.Index.All()
+		if runArgs.Plugin {
+			var err error
+			days, err = pluginDays()
+			if err != nil {
+				return err
 			}
+		}
 
-			for y := util.Ternary(runArgs.Year != 0, runArgs.Year, 2015); y <= maxYear; y++ {
-				maxDay := solutions.Index.GetCurrentDayForYear(y)
-				for d := uint(1); d <= maxDay; d++ {
-					if err := runDay(d, y); err != nil {
-						return err
-					}
+		targets := make([]solutions.IndexedDay, 0)
+		if runArgs.All {
+			for _, entry := range days {
+				if runArgs.Year != 0 && entry.Year != runArgs.Year {
+					continue
 				}
+
+				targets = append(targets, entry)
 			}
 		} else {
 			cDay, cYear := solutions.Index.GetCurrentDay()
+			if runArgs.Plugin && len(days) != 0 {
+				cDay, cYear = days[len(days)-1].Number, days[len(days)-1].Year
+			}
 
-			if cacheArgs.Day != 0 || cacheArgs.Year != 0 {
-				if cacheArgs.Day != 0 {
-					cDay = cacheArgs.Day
+			if runArgs.Day != 0 || runArgs.Year != 0 {
+				if runArgs.Day != 0 {
+					cDay = runArgs.Day
 				}
 
-				if cacheArgs.Year != 0 {
-					cYear = cacheArgs.Year
+				if runArgs.Year != 0 {
+					cYear = runArgs.Year
 				}
 			}
 
@@ -136,7 +263,32 @@ var runCommand = &cobra.Command{
 				cDay++
 			}
 
-			if err := runDay(cDay, cYear); err != nil {
+			targets = append(targets, solutions.IndexedDay{Year: cYear, Number: cDay})
+		}
+
+		// runners are built up front, so compiling never eats into the timeout.
+		if runArgs.Plugin {
+			for _, entry := range targets {
+				if _, err := buildRunner(entry.Number, entry.Year); err != nil {
+					return err
+				}
+			}
+		}
+
+		ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer cancel()
+
+		if runArgs.Timeout != 0 {
+			ctx, cancel = context.WithTimeout(ctx, runArgs.Timeout)
+			defer cancel()
+		}
+
+		for _, entry := range targets {
+			if runArgs.All {
+				fmt.Printf("Day %d/%d:\n", entry.Year, entry.Number)
+			}
+
+			if err := runDay(ctx, entry.Number, entry.Year); err != nil {
 				return err
 			}
 		}
@@ -148,11 +300,18 @@ var runCommand = &cobra.Command{
 func init() {
 	runCommand.PersistentFlags().UintVar(&runArgs.Year, "year", 0, "Specified year of solutions to run. If specified with --all, runs every day of that year.")
 	runCommand.PersistentFlags().UintVar(&runArgs.Day, "day", 0, "Specified day of solutions to run.")
-	runCommand.PersistentFlags().IntVar(&runArgs.Part, "part", -1, "0 or 1. Runs both by default.")
+	runCommand.PersistentFlags().IntVar(&runArgs.Part, "part", -1, "1 or 2. Runs both by default.")
 	runCommand.PersistentFlags().BoolVar(&runArgs.All, "all", false, "Run all days available (of all years if year is unspecified).")
 	runCommand.PersistentFlags().BoolVar(&runArgs.CacheAnswers, "cache-answers", false, "Overwrite existing input & solution with new data from these runs.")
 	runCommand.PersistentFlags().StringVar(&runArgs.InputMode, "input-mode", "cache", "How to obtain an input for the run. (cache/download/generate) (defaults to cache).")
 	runCommand.PersistentFlags().Uint64Var(&runArgs.InputComplexity, "input-complexity", 0, "Input complexity to generate at. Defaults to that specified by the problem's code.")
 
+	runCommand.PersistentFlags().DurationVar(&runArgs.Timeout, "timeout", 0, "Cancel the run after this long. Ctrl+C also cancels. (default: no timeout)")
+
+	runCommand.PersistentFlags().StringVar(&runArgs.Variant, "variant", "", "Variant of the day to run, or 'all' to run and compare every variant. (default: the default variant)")
+
+	pluginMode, _ := core.EEnvironmentVariable.PluginMode().Get()
+	runCommand.PersistentFlags().BoolVar(&runArgs.Plugin, "plugin", pluginMode != "", "Run solutions out of process, from a runner built from the source on demand, so aocf needn't be rebuilt for new days. (default: $AOCF_PLUGIN is set)")
+
 	RootCmd.AddCommand(runCommand)
 }
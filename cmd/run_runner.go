@@ -0,0 +1,138 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"github.com/Riven-Spell/advent_of_code_forever/plugin"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions"
+	"io"
+	"os"
+)
+
+// dayRunner runs a day's variants, either linked into aocf or out of process through a plugin runner.
+type dayRunner interface {
+	variants() (all []string, def string)
+	hasGenerator() bool
+	generate(ctx context.Context, complexity uint64, outputPath string) (*inputs.Solution, error)
+	run(ctx context.Context, variant, inputPath string, parts []int) solutions.DayResult
+	close()
+}
+
+// localRunner runs days registered in aocf's own index.
+type localRunner struct {
+	day, year uint
+}
+
+func (l localRunner) variants() (all []string, def string) {
+	all = solutions.Index.Variants(l.day, l.year)
+	for _, v := range all {
+		if solutions.Index.GetVariant(l.day, l.year, v) == solutions.Index.Get(l.day, l.year) {
+			def = v
+		}
+	}
+
+	return all, def
+}
+
+func (l localRunner) hasGenerator() bool {
+	return solutions.Index.Get(l.day, l.year).HasGenerator()
+}
+
+func (l localRunner) generate(ctx context.Context, complexity uint64, outputPath string) (*inputs.Solution, error) {
+	return solutions.GenerateTo(solutions.Index.Get(l.day, l.year), complexity, outputPath)
+}
+
+func (l localRunner) run(ctx context.Context, variant, inputPath string, parts []int) solutions.DayResult {
+	// streamed from disk, so giga inputs never have to fit in memory.
+	input := solutions.StreamInput(func() (io.ReadCloser, error) {
+		return os.Open(inputPath)
+	})
+
+	return solutions.RunDay(ctx, solutions.Index.GetVariant(l.day, l.year, variant), input, parts)
+}
+
+func (l localRunner) close() {}
+
+// pluginRunner runs a day out of process, from a runner built from the solutions repository.
+type pluginRunner struct {
+	*plugin.Runner
+}
+
+func (p pluginRunner) variants() (all []string, def string) {
+	return p.Hello.Variants, p.Hello.Default
+}
+
+func (p pluginRunner) hasGenerator() bool {
+	return p.Hello.Generator
+}
+
+func (p pluginRunner) generate(ctx context.Context, complexity uint64, outputPath string) (*inputs.Solution, error) {
+	return p.Generate(ctx, complexity, outputPath)
+}
+
+func (p pluginRunner) run(ctx context.Context, variant, inputPath string, parts []int) solutions.DayResult {
+	return p.RunDay(ctx, variant, inputPath, parts)
+}
+
+func (p pluginRunner) close() {
+	p.Close()
+}
+
+// openDayRunner finds a runner for the day: aocf's own index, or with --plugin, a runner built from the project root.
+func openDayRunner(cDay, cYear uint) (dayRunner, error) {
+	if !runArgs.Plugin {
+		if !solutions.Index.Has(cDay, cYear) {
+			return nil, fmt.Errorf("day %d/%d is not available", cYear, cDay)
+		}
+
+		return localRunner{day: cDay, year: cYear}, nil
+	}
+
+	bin, err := buildRunner(cDay, cYear)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := plugin.Start(bin)
+	if err != nil {
+		return nil, fmt.Errorf("day %d/%d: %w", cYear, cDay, err)
+	}
+
+	return pluginRunner{r}, nil
+}
+
+// buildRunner builds the day's plugin runner from the project root, or finds it already built.
+func buildRunner(cDay, cYear uint) (string, error) {
+	root, err := projectRoot()
+	if err != nil {
+		return "", err
+	}
+
+	binDir, err := inputs.Cache.PluginDir()
+	if err != nil {
+		return "", err
+	}
+
+	return plugin.Build(root, binDir, cDay, cYear)
+}
+
+// pluginDays lists the days --all runs in plugin mode: every day with code, rather than every day in aocf's index.
+func pluginDays() ([]solutions.IndexedDay, error) {
+	root, err := projectRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	refs, err := plugin.Days(root)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]solutions.IndexedDay, 0, len(refs))
+	for _, ref := range refs {
+		out = append(out, solutions.IndexedDay{Year: ref.Year, Number: ref.Day})
+	}
+
+	return out, nil
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"github.com/Riven-Spell/advent_of_code_forever/transaction"
+	"github.com/spf13/cobra"
+)
+
+var undoArgs = struct {
+	Count uint
+	List  bool
+}{}
+
+// beginTransaction starts a transaction whose removed files are kept in the cache's trash, so it can be undone.
+func beginTransaction(description string) (*transaction.Transaction, error) {
+	trashDir, err := inputs.Cache.TrashDir()
+	if err != nil {
+		return nil, fmt.Errorf("cannot find trash: %w", err)
+	}
+
+	return transaction.Begin(trashDir, description)
+}
+
+var undoCommand = &cobra.Command{
+	Use:   "undo [--count <n>] [--list]",
+	Short: "Undo the last create/delete operations.",
+	Long:  "Restores the code directories, importer, cached inputs and solutions changed by the last n create/delete operations. Rebuild aocf afterward.\nAnything changed since (e.g. edits to a created day) is moved to the trash rather than lost, and each undo can itself be undone.",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		trashDir, err := inputs.Cache.TrashDir()
+		if err != nil {
+			return fmt.Errorf("cannot find trash: %w", err)
+		}
+
+		if undoArgs.List {
+			journal, err := transaction.ReadJournal(trashDir)
+			if err != nil {
+				return err
+			}
+
+			if len(journal) == 0 {
+				fmt.Println("Nothing to undo.")
+			}
+
+			for i := len(journal) - 1; i >= 0; i-- {
+				fmt.Printf("%d: %s (%s)\n", len(journal)-i, journal[i].Description, journal[i].Time.Local().Format("2006-01-02 15:04:05"))
+			}
+
+			return nil
+		}
+
+		undone, err := transaction.Undo(trashDir, int(undoArgs.Count))
+		for _, record := range undone {
+			fmt.Printf("Undid %s\n", record.Description)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if len(undone) == 0 {
+			fmt.Println("Nothing to undo.")
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	undoCommand.PersistentFlags().UintVar(&undoArgs.Count, "count", 1, "How many operations to undo.")
+	undoCommand.PersistentFlags().BoolVar(&undoArgs.List, "list", false, "List the operations that can be undone, newest first.")
+
+	RootCmd.AddCommand(undoCommand)
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/calendar"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
+	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions/solution_templates"
+	"github.com/spf13/cobra"
+	"math/rand"
+	"time"
+)
+
+var waitArgs = struct {
+	NoCreate   bool
+	MaxJitter  time.Duration
+	Retries    int
+	RetryDelay time.Duration
+}{}
+
+// waiter runs the unlock flow: count down, fetch the input and puzzle text, then scaffold the day.
+// Every dependency is a field so the flow can run against a fake clock and a fake AoC server.
+type waiter struct {
+	Clock      core.Clock
+	Cache      *inputs.InputCache
+	Create     func(day, year uint) error // nil skips scaffolding
+	MaxJitter  time.Duration
+	Retries    int
+	RetryDelay time.Duration
+	Status     func(format string, a ...any)
+}
+
+// countdown blocks until the target time, reporting the remaining time once per second.
+func (w *waiter) countdown(day, year uint, at time.Time) {
+	for {
+		remaining := at.Sub(w.Clock.Now())
+		if remaining <= 0 {
+			w.Status("\rDay %d/%d unlocked!                    \n", year, day)
+			return
+		}
+
+		w.Status("\rDay %d/%d unlocks in %s   ", year, day, remaining.Round(time.Second).String())
+		<-w.Clock.After(minDuration(remaining, time.Second))
+	}
+}
+
+func minDuration(a, b time.Duration) time.Duration {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+// retry runs op until it succeeds or retries run out, backing off linearly.
+func (w *waiter) retry(what string, op func() error) error {
+	var err error
+	for attempt := 0; attempt <= w.Retries; attempt++ {
+		if attempt != 0 {
+			w.Status("Failed to %s (%s), retrying (%d/%d)\n", what, err.Error(), attempt, w.Retries)
+			<-w.Clock.After(w.RetryDelay * time.Duration(attempt))
+		}
+
+		err = op()
+		if err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to %s after %d attempts: %w", what, w.Retries+1, err)
+}
+
+func (w *waiter) Run() error {
+	day, year, at := calendar.NextUnlock(w.Clock.Now())
+	w.countdown(day, year, at)
+
+	// Don't hit AoC at the exact same instant as everyone else.
+	if w.MaxJitter > 0 {
+		<-w.Clock.After(time.Duration(rand.Int63n(int64(w.MaxJitter))))
+	}
+
+	err := w.retry("download input", func() error {
+		return w.Cache.DownloadInput(day, year, true)
+	})
+	if err != nil {
+		return err
+	}
+	w.Status("Downloaded input for %d/%d\n", year, day)
+
+	err = w.retry("download puzzle", func() error {
+		return w.Cache.DownloadPuzzle(day, year, true)
+	})
+	if err != nil {
+		return err
+	}
+	w.Status("Downloaded puzzle for %d/%d\n", year, day)
+
+	// created last, so the template can use the puzzle title and input.
+	if w.Create != nil && !solutions.Index.Has(day, year) {
+		err = w.Create(day, year)
+		if err != nil {
+			return err
+		}
+		w.Status("Created day %d/%d\n", year, day)
+	}
+
+	return nil
+}
+
+var waitCommand = &cobra.Command{
+	Use:   "wait [--no-create] [--jitter <duration>] [--retries <n>]",
+	Short: "Count down to the next puzzle unlock, then create the day and fetch its input & puzzle text.",
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		w := &waiter{
+			Clock:      core.SystemClock,
+			Cache:      inputs.Cache,
+			MaxJitter:  waitArgs.MaxJitter,
+			Retries:    waitArgs.Retries,
+			RetryDelay: waitArgs.RetryDelay,
+			Status: func(format string, a ...any) {
+				fmt.Printf(format, a...)
+			},
+		}
+
+		if !waitArgs.NoCreate {
+			workDir, err := projectRoot()
+			if err != nil {
+				fmt.Println(err.Error())
+				return nil
+			}
+
+			w.Create = func(day, year uint) error {
+				return createDay(workDir, day, year, createOptions{Template: solution_templates.DefaultSolutionTemplate, Verify: true})
+			}
+		}
+
+		err := w.Run()
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	waitCommand.PersistentFlags().BoolVar(&waitArgs.NoCreate, "no-create", false, "Only fetch the input & puzzle, don't scaffold the day's code.")
+	waitCommand.PersistentFlags().DurationVar(&waitArgs.MaxJitter, "jitter", 5*time.Second, "Maximum random delay after unlock before downloading.")
+	waitCommand.PersistentFlags().IntVar(&waitArgs.Retries, "retries", 5, "How many times to retry failed downloads.")
+	waitCommand.PersistentFlags().DurationVar(&waitArgs.RetryDelay, "retry-delay", 2*time.Second, "Base delay between retries, increasing with every attempt.")
+
+	RootCmd.AddCommand(waitCommand)
+}
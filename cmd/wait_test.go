@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/calendar"
+	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock advances only when waited on, so countdowns finish instantly.
+type fakeClock struct {
+	now    time.Time
+	waited []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(d)
+	c.waited = append(c.waited, d)
+
+	out := make(chan time.Time, 1)
+	out <- c.now
+	return out
+}
+
+// fakeAoC serves one day's input and puzzle, failing the first inputFailures input requests.
+func fakeAoC(t *testing.T, day, year uint, inputFailures int) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case fmt.Sprintf("/%d/day/%d/input", year, day):
+			if inputFailures > 0 {
+				inputFailures--
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = fmt.Fprint(w, "1\n2\n3\n")
+		case fmt.Sprintf("/%d/day/%d", year, day):
+			_, _ = fmt.Fprint(w, "<html><main><article><h2>--- Day 5: Test ---</h2><p>Some &amp; text.</p></article></main></html>")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func newTestWaiter(t *testing.T, clock *fakeClock, srv *httptest.Server) (*waiter, *strings.Builder) {
+	t.Helper()
+
+	t.Setenv("AOCF_SESSION_COOKIE", "token")
+	t.Setenv("AOCF_BASE_URL", srv.URL)
+
+	cache := inputs.NewInputCache(t.TempDir())
+	cache.Client = srv.Client()
+
+	status := &strings.Builder{}
+	return &waiter{
+		Clock:      clock,
+		Cache:      cache,
+		Retries:    2,
+		RetryDelay: time.Second,
+		Status: func(format string, a ...any) {
+			_, _ = fmt.Fprintf(status, format, a...)
+		},
+	}, status
+}
+
+func TestWaiterCountdown(t *testing.T) {
+	at := calendar.UnlockTime(2022, 5)
+	clock := &fakeClock{now: at.Add(-2500 * time.Millisecond)}
+	w, status := newTestWaiter(t, clock, fakeAoC(t, 5, 2022, 0))
+
+	w.countdown(5, 2022, at)
+
+	if !clock.now.Equal(at) {
+		t.Errorf("countdown stopped at %s, want %s", clock.now, at)
+	}
+
+	// ticks once a second, then whatever's left over.
+	want := []time.Duration{time.Second, time.Second, 500 * time.Millisecond}
+	if fmt.Sprint(clock.waited) != fmt.Sprint(want) {
+		t.Errorf("waited %v, want %v", clock.waited, want)
+	}
+
+	for _, line := range []string{"unlocks in 3s", "unlocks in 2s", "unlocks in 1s", "Day 2022/5 unlocked!"} {
+		if !strings.Contains(status.String(), line) {
+			t.Errorf("status %q does not contain %q", status.String(), line)
+		}
+	}
+}
+
+func TestWaiterRun(t *testing.T) {
+	at := calendar.UnlockTime(2022, 5)
+	clock := &fakeClock{now: at.Add(-time.Minute)}
+	w, status := newTestWaiter(t, clock, fakeAoC(t, 5, 2022, 1))
+
+	var created []string
+	w.Create = func(day, year uint) error {
+		// the day is scaffolded from what was downloaded.
+		if _, err := w.Cache.GetPuzzle(day, year); err != nil {
+			t.Errorf("puzzle not cached before create: %s", err)
+		}
+
+		created = append(created, fmt.Sprintf("%d/%d", year, day))
+		return nil
+	}
+
+	err := w.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input, err := w.Cache.GetInput(5, 2022)
+	if err != nil || input != "1\n2\n3\n" {
+		t.Errorf("cached input = %q, %v", input, err)
+	}
+
+	puzzle, err := w.Cache.GetPuzzle(5, 2022)
+	if err != nil || puzzle != "--- Day 5: Test ---\nSome & text.\n" {
+		t.Errorf("cached puzzle = %q, %v", puzzle, err)
+	}
+
+	if fmt.Sprint(created) != "[2022/5]" {
+		t.Errorf("created %v, want [2022/5]", created)
+	}
+
+	if !strings.Contains(status.String(), "retrying (1/2)") {
+		t.Errorf("status %q does not mention the retry", status.String())
+	}
+
+	// a minute of countdown, then a second of backoff for the failed input.
+	if want := at.Add(time.Second); !clock.now.Equal(want) {
+		t.Errorf("finished at %s, want %s", clock.now, want)
+	}
+}
+
+func TestWaiterGivesUp(t *testing.T) {
+	at := calendar.UnlockTime(2022, 5)
+	clock := &fakeClock{now: at.Add(-time.Second)}
+	w, _ := newTestWaiter(t, clock, fakeAoC(t, 5, 2022, 3))
+
+	w.Create = func(day, year uint) error {
+		t.Error("created a day without its input")
+		return nil
+	}
+
+	err := w.Run()
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("Run() = %v, want an error after 3 attempts", err)
+	}
+}
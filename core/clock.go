@@ -0,0 +1,21 @@
+package core
+
+import "time"
+
+// Clock is the time source for anything that waits on AoC's schedule, so it can be faked.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+var SystemClock Clock = systemClock{}
@@ -4,6 +4,10 @@ import "os"
 
 var EnvironmentVariables = []EnvironmentVariable{
 	EEnvironmentVariable.AuthToken(),
+	EEnvironmentVariable.BaseURL(),
+	EEnvironmentVariable.LeaderboardID(),
+	EEnvironmentVariable.ProjectRoot(),
+	EEnvironmentVariable.PluginMode(),
 }
 
 type EnvironmentVariable struct {
@@ -33,3 +37,29 @@ func (*eEnvironmentVariable) AuthToken() EnvironmentVariable {
 		Secret: true,
 	}
 }
+
+func (*eEnvironmentVariable) BaseURL() EnvironmentVariable {
+	return EnvironmentVariable{
+		Name:    "AOCF_BASE_URL",
+		Default: "https://adventofcode.com",
+	}
+}
+
+func (*eEnvironmentVariable) LeaderboardID() EnvironmentVariable {
+	return EnvironmentVariable{
+		Name: "AOCF_LEADERBOARD_ID",
+	}
+}
+
+func (*eEnvironmentVariable) ProjectRoot() EnvironmentVariable {
+	return EnvironmentVariable{
+		Name: "AOCF_ROOT",
+	}
+}
+
+// PluginMode makes `aocf run` run solutions out of process by default, when set to anything.
+func (*eEnvironmentVariable) PluginMode() EnvironmentVariable {
+	return EnvironmentVariable{
+		Name: "AOCF_PLUGIN",
+	}
+}
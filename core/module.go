@@ -0,0 +1,85 @@
+package core
+
+import (
+	"fmt"
+	"golang.org/x/mod/modfile"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ModulePath is the import path of advent_of_code_forever, as declared in go.mod.
+const ModulePath = "github.com/Riven-Spell/advent_of_code_forever"
+
+// SolutionCodePath is the import path solutions are laid out under, as <year>/day<num>.
+const SolutionCodePath = ModulePath + "/solutions/solution_code"
+
+// SolutionCodeDir is where solutions live relative to a project root, in advent_of_code_forever and any other solutions repository.
+const SolutionCodeDir = "solutions/solution_code"
+
+// ModulePathOf reads the module path declared in dir's go.mod.
+func ModulePathOf(dir string) (string, error) {
+	goMod := filepath.Join(dir, "go.mod")
+
+	buf, err := os.ReadFile(goMod)
+	if err != nil {
+		return "", err
+	}
+
+	module := modfile.ModulePath(buf)
+	if module == "" {
+		return "", fmt.Errorf("%s declares no module", goMod)
+	}
+
+	return module, nil
+}
+
+// SolutionCodePathOf is the import path of the solutions in the project at root.
+func SolutionCodePathOf(root string) (string, error) {
+	module, err := ModulePathOf(root)
+	if err != nil {
+		return "", err
+	}
+
+	return module + "/" + SolutionCodeDir, nil
+}
+
+// SolutionCodePathFor returns the import path of the solution_code pkg is in, whichever module that belongs to.
+// ok is false if pkg isn't under a solution_code.
+func SolutionCodePathFor(pkg string) (codePath string, ok bool) {
+	i := strings.Index(pkg, "/"+SolutionCodeDir+"/")
+	if i < 0 {
+		return "", false
+	}
+
+	return pkg[:i+len(SolutionCodeDir)+1], true
+}
+
+// IsProjectRoot reports whether dir is the root of a solutions repository:
+// advent_of_code_forever itself, or any module with the same solutions/solution_code layout.
+func IsProjectRoot(dir string) bool {
+	if _, err := ModulePathOf(dir); err != nil {
+		return false
+	}
+
+	stat, err := os.Stat(filepath.Join(dir, filepath.FromSlash(SolutionCodeDir)))
+	return err == nil && stat.IsDir()
+}
+
+// FindProjectRoot walks up from dir to the root of the solutions repository it's in.
+func FindProjectRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+
+	for start := dir; ; dir = filepath.Dir(dir) {
+		if IsProjectRoot(dir) {
+			return dir, nil
+		}
+
+		if filepath.Dir(dir) == dir {
+			return "", fmt.Errorf("no go.mod with a %s directory in %s or any parent directory", SolutionCodeDir, start)
+		}
+	}
+}
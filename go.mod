@@ -2,8 +2,12 @@ module github.com/Riven-Spell/advent_of_code_forever
 
 go 1.19
 
+require (
+	github.com/spf13/cobra v1.6.1
+	golang.org/x/mod v0.20.0
+)
+
 require (
 	github.com/inconshreveable/mousetrap v1.0.1 // indirect
-	github.com/spf13/cobra v1.6.1 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 )
@@ -0,0 +1,208 @@
+package inputs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/util"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+type AnswerKind string
+
+type eAnswerKind struct{}
+
+var EAnswerKind = &eAnswerKind{}
+
+// Int covers integers of any width, including integral floats and JSON numbers.
+func (*eAnswerKind) Int() AnswerKind { return "int" }
+
+// String is any single-line textual answer.
+func (*eAnswerKind) String() AnswerKind { return "string" }
+
+// Art is a multi-line answer that util.OCR could not read as block letters.
+func (*eAnswerKind) Art() AnswerKind { return "art" }
+
+// Answer is the canonical form of a solution's answer. Two answers are equal if their kind and value are,
+// except that an Int also equals a String spelling exactly the same digits, as answers scraped or typed as text do.
+// Strings are never parsed as numbers, so "007" and "+5" don't equal 7 and 5.
+type Answer struct {
+	Kind  AnswerKind `json:"kind"`
+	Value string     `json:"value"`
+}
+
+func (a Answer) String() string {
+	if a.Kind == EAnswerKind.Art() {
+		return "\n" + a.Value
+	}
+
+	return a.Value
+}
+
+func (a Answer) Equal(b Answer) bool {
+	if a.Kind != b.Kind {
+		textual := func(k AnswerKind) bool { return k == EAnswerKind.Int() || k == EAnswerKind.String() }
+		return textual(a.Kind) && textual(b.Kind) && a.Value == b.Value
+	}
+
+	return a.Value == b.Value
+}
+
+func normalizeText(s string) Answer {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+
+	lines := strings.Split(s, "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t")
+	}
+
+	// drop leading & trailing blank lines
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) > 1 {
+		art := strings.Join(lines, "\n")
+
+		// block letters are compared (and shown) as the text they spell.
+		if text, err := util.OCRString(art); err == nil {
+			return Answer{Kind: EAnswerKind.String(), Value: text}
+		}
+
+		return Answer{Kind: EAnswerKind.Art(), Value: art}
+	}
+
+	return Answer{Kind: EAnswerKind.String(), Value: strings.TrimSpace(strings.Join(lines, ""))}
+}
+
+// normalizeNumber reads a JSON number exactly when it is an integer, and as a float otherwise.
+func normalizeNumber(n json.Number) Answer {
+	if i, ok := new(big.Int).SetString(n.String(), 10); ok {
+		return Answer{Kind: EAnswerKind.Int(), Value: i.String()}
+	}
+
+	if f, err := n.Float64(); err == nil {
+		return normalizeFloat(f)
+	}
+
+	return normalizeText(n.String())
+}
+
+func normalizeFloat(f float64) Answer {
+	if f == math.Trunc(f) && !math.IsInf(f, 0) {
+		n, _ := big.NewFloat(f).Int(nil)
+		return Answer{Kind: EAnswerKind.Int(), Value: n.String()}
+	}
+
+	return Answer{Kind: EAnswerKind.String(), Value: strconv.FormatFloat(f, 'g', -1, 64)}
+}
+
+// NormalizeAnswer converts a raw answer into its canonical form. ok is false for nil.
+// Integers of any width become Int, strings are trimmed but stay String, multi-line text and [][]bool
+// grids become Art, unless they spell out AoC block letters, in which case they become the String they spell.
+func NormalizeAnswer(v any) (out Answer, ok bool) {
+	switch a := v.(type) {
+	case nil:
+		return Answer{}, false
+	case Answer:
+		return a, true
+	case *Answer:
+		if a == nil {
+			return Answer{}, false
+		}
+		return *a, true
+	case int:
+		return Answer{Kind: EAnswerKind.Int(), Value: strconv.FormatInt(int64(a), 10)}, true
+	case int8:
+		return Answer{Kind: EAnswerKind.Int(), Value: strconv.FormatInt(int64(a), 10)}, true
+	case int16:
+		return Answer{Kind: EAnswerKind.Int(), Value: strconv.FormatInt(int64(a), 10)}, true
+	case int32:
+		return Answer{Kind: EAnswerKind.Int(), Value: strconv.FormatInt(int64(a), 10)}, true
+	case int64:
+		return Answer{Kind: EAnswerKind.Int(), Value: strconv.FormatInt(a, 10)}, true
+	case uint:
+		return Answer{Kind: EAnswerKind.Int(), Value: strconv.FormatUint(uint64(a), 10)}, true
+	case uint8:
+		return Answer{Kind: EAnswerKind.Int(), Value: strconv.FormatUint(uint64(a), 10)}, true
+	case uint16:
+		return Answer{Kind: EAnswerKind.Int(), Value: strconv.FormatUint(uint64(a), 10)}, true
+	case uint32:
+		return Answer{Kind: EAnswerKind.Int(), Value: strconv.FormatUint(uint64(a), 10)}, true
+	case uint64:
+		return Answer{Kind: EAnswerKind.Int(), Value: strconv.FormatUint(a, 10)}, true
+	case *big.Int:
+		return Answer{Kind: EAnswerKind.Int(), Value: a.String()}, true
+	case float32:
+		return normalizeFloat(float64(a)), true
+	case float64:
+		return normalizeFloat(a), true
+	case json.Number:
+		return normalizeNumber(a), true
+	case string:
+		return normalizeText(a), true
+	case []string:
+		return normalizeText(strings.Join(a, "\n")), true
+	case [][]bool:
+		rows := make([]string, len(a))
+		for y, row := range a {
+			line := strings.Builder{}
+			for _, lit := range row {
+				line.WriteByte(util.Ternary[byte](lit, '#', '.'))
+			}
+			rows[y] = line.String()
+		}
+		return normalizeText(strings.Join(rows, "\n")), true
+	case []byte:
+		return normalizeText(string(a)), true
+	case fmt.Stringer:
+		return normalizeText(a.String()), true
+	default:
+		return normalizeText(fmt.Sprint(a)), true
+	}
+}
+
+// AnswersEqual compares two raw or canonical answers by their canonical form.
+func AnswersEqual(a, b any) bool {
+	na, okA := NormalizeAnswer(a)
+	nb, okB := NormalizeAnswer(b)
+
+	return okA == okB && na.Equal(nb)
+}
+
+// UnmarshalJSON accepts both the typed {"kind", "value"} form and bare values written before answers were typed.
+func (a *Answer) UnmarshalJSON(buf []byte) error {
+	type typed Answer
+	var t typed
+
+	trimmed := bytes.TrimSpace(buf)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		if err := json.Unmarshal(trimmed, &t); err != nil {
+			return err
+		}
+
+		*a = Answer(t)
+		return nil
+	}
+
+	var raw any
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber() // keep large integers exact
+	if err := decoder.Decode(&raw); err != nil {
+		return err
+	}
+
+	normalized, ok := NormalizeAnswer(raw)
+	if !ok {
+		return fmt.Errorf("answer cannot be null")
+	}
+
+	*a = normalized
+	return nil
+}
@@ -0,0 +1,116 @@
+package inputs
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+// hi spells HI in the 6-row font.
+const hi = "#..#.###\n#..#..#.\n####..#.\n#..#..#.\n#..#..#.\n#..#.###"
+
+func TestNormalizeAnswer(t *testing.T) {
+	big70, _ := new(big.Int).SetString("1180591620717411303424", 10)
+
+	tests := []struct {
+		name string
+		in   any
+		want Answer
+	}{
+		{"int", 42, Answer{EAnswerKind.Int(), "42"}},
+		{"negative int8", int8(-5), Answer{EAnswerKind.Int(), "-5"}},
+		{"max uint64", ^uint64(0), Answer{EAnswerKind.Int(), "18446744073709551615"}},
+		{"big int", big70, Answer{EAnswerKind.Int(), "1180591620717411303424"}},
+		{"integral float", 3.0, Answer{EAnswerKind.Int(), "3"}},
+		{"fractional float", 2.5, Answer{EAnswerKind.String(), "2.5"}},
+		{"json integer", json.Number("1180591620717411303424"), Answer{EAnswerKind.Int(), "1180591620717411303424"}},
+		{"json integral float", json.Number("3.0"), Answer{EAnswerKind.Int(), "3"}},
+		{"trimmed string", "  abc \n", Answer{EAnswerKind.String(), "abc"}},
+		{"leading zeros stay text", "01029498", Answer{EAnswerKind.String(), "01029498"}},
+		{"sign stays text", "+5", Answer{EAnswerKind.String(), "+5"}},
+		{"block letters", hi, Answer{EAnswerKind.String(), "HI"}},
+		{"block letters with trailing newlines", "\n" + hi + "\n\n", Answer{EAnswerKind.String(), "HI"}},
+		{"crlf block letters", "#..#.###\r\n#..#..#.\r\n####..#.\r\n#..#..#.\r\n#..#..#.\r\n#..#.###", Answer{EAnswerKind.String(), "HI"}},
+		{"unknown glyph", "##\n##", Answer{EAnswerKind.Art(), "##\n##"}},
+		{"bool grid", [][]bool{{true, false}, {false, true}}, Answer{EAnswerKind.Art(), "#.\n.#"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizeAnswer(tt.in)
+			if !ok || got != tt.want {
+				t.Errorf("NormalizeAnswer(%#v) = %+v, %t, want %+v", tt.in, got, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := NormalizeAnswer(nil); ok {
+		t.Error("NormalizeAnswer(nil) should not be ok")
+	}
+}
+
+func TestAnswersEqual(t *testing.T) {
+	tests := []struct {
+		a, b any
+		want bool
+	}{
+		{42, int64(42), true},
+		{42, 42.0, true},
+		{42, "42", true},
+		{42, " 42\n", true},
+		{1029498, "01029498", false},
+		{5, "+5", false},
+		{"007", "7", false},
+		{2.5, "2.5", true},
+		{"HI", hi, true},
+		{"HI", "##\n##", false},
+		{json.Number("12345678901234567890"), uint64(12345678901234567890), true},
+		{nil, 0, false},
+		{nil, nil, true},
+	}
+
+	for _, tt := range tests {
+		if got := AnswersEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("AnswersEqual(%#v, %#v) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestAnswerJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Answer
+	}{
+		{`{"kind": "int", "value": "5"}`, Answer{EAnswerKind.Int(), "5"}},
+		{`{"kind": "string", "value": "007"}`, Answer{EAnswerKind.String(), "007"}},
+		{`12345678901234567890123`, Answer{EAnswerKind.Int(), "12345678901234567890123"}},
+		{`3.0`, Answer{EAnswerKind.Int(), "3"}},
+		{`"007"`, Answer{EAnswerKind.String(), "007"}},
+	}
+
+	for _, tt := range tests {
+		var got Answer
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil || got != tt.want {
+			t.Errorf("unmarshal %s = %+v, %v, want %+v", tt.in, got, err, tt.want)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`null`), new(Answer)); err == nil {
+		t.Error("a null answer should not unmarshal")
+	}
+
+	// a solution survives the cache round-trip, whatever its answers started as.
+	buf, err := json.Marshal(Solution{A: 42, B: "007"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var s Solution
+	if err := json.Unmarshal(buf, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	if !AnswersEqual(s.A, 42) || !AnswersEqual(s.B, "007") || AnswersEqual(s.B, 7) {
+		t.Errorf("solution did not survive the round-trip: %s -> %+v", buf, s)
+	}
+}
@@ -1,9 +1,11 @@
 package inputs
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/calendar"
 	"github.com/Riven-Spell/advent_of_code_forever/core"
 	"io"
 	"net/http"
@@ -14,18 +16,61 @@ import (
 
 type InputCache struct {
 	cacheDir string
+
+	// Client downloads inputs and puzzles; nil uses http.DefaultClient.
+	Client *http.Client
 }
 
+// Solution holds the expected answers to a day's parts. Answers may be raw values (e.g. from a generator);
+// they are stored as typed Answers, and come back from the cache as Answer.
 type Solution struct {
 	A, B any
 }
 
+type storedSolution struct {
+	A, B *Answer
+}
+
+func (s Solution) MarshalJSON() ([]byte, error) {
+	out := storedSolution{}
+	if a, ok := NormalizeAnswer(s.A); ok {
+		out.A = &a
+	}
+	if b, ok := NormalizeAnswer(s.B); ok {
+		out.B = &b
+	}
+
+	return json.Marshal(out)
+}
+
+func (s *Solution) UnmarshalJSON(buf []byte) error {
+	var stored storedSolution
+	if err := json.Unmarshal(buf, &stored); err != nil {
+		return err
+	}
+
+	s.A, s.B = nil, nil
+	if stored.A != nil {
+		s.A = *stored.A
+	}
+	if stored.B != nil {
+		s.B = *stored.B
+	}
+
+	return nil
+}
+
 func (s *Solution) Empty() bool {
 	return s == nil || (s.A == nil && s.B == nil)
 }
 
 var Cache = &InputCache{}
 
+// NewInputCache returns a cache kept in dir, rather than ~/.aocf.
+func NewInputCache(dir string) *InputCache {
+	return &InputCache{cacheDir: dir}
+}
+
 func (i *InputCache) GetCacheDir() (string, error) {
 	if i.cacheDir != "" {
 		return i.cacheDir, nil
@@ -55,21 +100,75 @@ func (i *InputCache) HasCachedInput(day, year uint) bool {
 	return err == nil
 }
 
+// CachedPaths lists a day's cached input, solution and puzzle text, or those of every day in the year if day is 0.
+// Other files in the year's directory (e.g. leaderboards) are not included. The files may not exist.
+func (i *InputCache) CachedPaths(day, year uint) ([]string, error) {
+	cDir, err := i.GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := calendar.ValidateYear(year); err != nil {
+		return nil, err
+	}
+
+	if calendar.ValidateDay(day, year) != nil {
+		out := make([]string, 0)
+		for d := uint(1); d <= calendar.DaysIn(year); d++ {
+			paths, err := i.CachedPaths(d, year)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, paths...)
+		}
+
+		return out, nil
+	}
+
+	return []string{
+		filepath.Join(cDir, fmt.Sprintf("%d/%d.txt", year, day)),
+		filepath.Join(cDir, fmt.Sprintf("%d/%d.solution.txt", year, day)),
+		filepath.Join(cDir, fmt.Sprintf("%d/%d.puzzle.txt", year, day)),
+	}, nil
+}
+
+// TrashDir is where removed files are kept, so they can be restored by `aocf undo`.
+func (i *InputCache) TrashDir() (string, error) {
+	cDir, err := i.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cDir, "trash"), nil
+}
+
+// PluginDir is where day runners are built for `aocf run --plugin`.
+func (i *InputCache) PluginDir() (string, error) {
+	cDir, err := i.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cDir, "plugins"), nil
+}
+
 func (i *InputCache) DeleteInput(day, year uint) error {
 	cDir, err := i.GetCacheDir()
 	if err != nil {
 		return err
 	}
 
-	if year < 2015 {
-		return errors.New("cannot delete non-existent AoC year (< 2015)")
+	if err := calendar.ValidateYear(year); err != nil {
+		return fmt.Errorf("cannot delete inputs: %w", err)
 	}
 
 	inputPath := filepath.Join(cDir, fmt.Sprintf("%d/%d.txt", year, day))
 	solutionPath := filepath.Join(cDir, fmt.Sprintf("%d/%d.solution.txt", year, day))
+	puzzlePath := filepath.Join(cDir, fmt.Sprintf("%d/%d.puzzle.txt", year, day))
 
-	if day > 0 && day <= 25 {
+	if calendar.ValidateDay(day, year) == nil {
 		_ = os.Remove(solutionPath) // it's OK if this fails because maybe it doesn't exist.
+		_ = os.Remove(puzzlePath)
 
 		return os.Remove(inputPath)
 	} else {
@@ -180,17 +279,19 @@ func (i *InputCache) PutInput(day, year uint, input io.Reader, replace bool) err
 	return f.Close()
 }
 
-func (i *InputCache) DownloadInput(day, year uint, replace bool) error {
-	targetURL := fmt.Sprintf("https://adventofcode.com/%d/day/%d/input", year, day)
+// aocGet performs an authenticated GET against AoC (or AOCF_BASE_URL) for the given path.
+func (i *InputCache) aocGet(path string) (*http.Response, error) {
+	baseURL, _ := core.EEnvironmentVariable.BaseURL().Get()
+	targetURL := strings.TrimSuffix(baseURL, "/") + path
 
 	req, err := http.NewRequest(http.MethodGet, targetURL, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	token, def := core.EEnvironmentVariable.AuthToken().Get()
 	if def {
-		return errors.New("auth token not specified, cannot download input.")
+		return nil, errors.New("auth token not specified, cannot download input.")
 	}
 
 	req.AddCookie(&http.Cookie{
@@ -198,7 +299,66 @@ func (i *InputCache) DownloadInput(day, year uint, replace bool) error {
 		Value: token,
 	})
 
-	resp, err := http.DefaultClient.Do(req)
+	client := i.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("request for %s failed: %s", path, resp.Status)
+	}
+
+	return resp, nil
+}
+
+// WriteInput lets write stream an input straight to disk, for inputs too large to build in memory.
+// The partial input is removed if write fails.
+func (i *InputCache) WriteInput(day, year uint, replace bool, write func(w io.Writer) error) error {
+	cDir, err := i.GetCacheDir()
+	if err != nil {
+		return err
+	}
+
+	inputPath := filepath.Join(cDir, fmt.Sprintf("%d/%d.txt", year, day))
+	err = os.MkdirAll(filepath.Dir(inputPath), 0755)
+	if err != nil {
+		return err
+	}
+
+	if !replace {
+		_, err = os.Stat(inputPath)
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("cannot put input: file either exists, or stat failed: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(inputPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(f)
+	err = write(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(inputPath)
+		return err
+	}
+
+	return f.Close()
+}
+
+func (i *InputCache) DownloadInput(day, year uint, replace bool) error {
+	resp, err := i.aocGet(fmt.Sprintf("/%d/day/%d/input", year, day))
 	if err != nil {
 		return err
 	}
@@ -233,6 +393,16 @@ func (i *InputCache) GetInput(day, year uint) (string, error) {
 	return string(buf), nil
 }
 
+// OpenInput opens the cached input for streaming, rather than reading it all into memory.
+func (i *InputCache) OpenInput(day, year uint) (io.ReadCloser, error) {
+	cDir, err := i.GetCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(filepath.Join(cDir, fmt.Sprintf("%d/%d.txt", year, day)))
+}
+
 func (i *InputCache) GetInputAndSolution(day, year uint) (string, *Solution, error) {
 	input, err := i.GetInput(day, year)
 	if err != nil {
@@ -0,0 +1,88 @@
+package inputs
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	articleRegex = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagRegex     = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// puzzleText reduces a puzzle page to the plain text of its <article> blocks (part 2 appears once part 1 is solved).
+func puzzleText(page string) string {
+	articles := articleRegex.FindAllStringSubmatch(page, -1)
+	out := make([]string, 0, len(articles))
+
+	for _, a := range articles {
+		text := a[1]
+		text = strings.ReplaceAll(text, "</p>", "</p>\n")
+		text = strings.ReplaceAll(text, "</h2>", "</h2>\n")
+		text = tagRegex.ReplaceAllString(text, "")
+		out = append(out, strings.TrimSpace(html.UnescapeString(text)))
+	}
+
+	return strings.Join(out, "\n\n") + "\n"
+}
+
+func (i *InputCache) puzzlePath(day, year uint) (string, error) {
+	cDir, err := i.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cDir, fmt.Sprintf("%d/%d.puzzle.txt", year, day)), nil
+}
+
+// DownloadPuzzle fetches the puzzle description and caches it as plain text.
+func (i *InputCache) DownloadPuzzle(day, year uint, replace bool) error {
+	puzzlePath, err := i.puzzlePath(day, year)
+	if err != nil {
+		return err
+	}
+
+	if !replace {
+		_, err = os.Stat(puzzlePath)
+		if !os.IsNotExist(err) {
+			return fmt.Errorf("cannot put puzzle: file either exists, or stat failed: %w", err)
+		}
+	}
+
+	resp, err := i.aocGet(fmt.Sprintf("/%d/day/%d", year, day))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(puzzlePath), 0755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(puzzlePath, []byte(puzzleText(string(buf))), 0755)
+}
+
+func (i *InputCache) GetPuzzle(day, year uint) (string, error) {
+	puzzlePath, err := i.puzzlePath(day, year)
+	if err != nil {
+		return "", err
+	}
+
+	buf, err := os.ReadFile(puzzlePath)
+	if err != nil {
+		return "", err
+	}
+
+	return string(buf), nil
+}
@@ -0,0 +1,156 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
+	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MinRefreshInterval is the minimum time AoC asks clients to wait between leaderboard requests.
+const MinRefreshInterval = 15 * time.Minute
+
+// Fetcher downloads private leaderboards and caches them alongside inputs.
+// Every field is optional; zero values fall back to the environment, http.DefaultClient, inputs.Cache and time.Now.
+type Fetcher struct {
+	BaseURL string
+	Client  *http.Client
+	Cache   *inputs.InputCache
+	Now     func() time.Time
+}
+
+var Default = &Fetcher{}
+
+func (f *Fetcher) now() time.Time {
+	if f.Now != nil {
+		return f.Now()
+	}
+
+	return time.Now()
+}
+
+func (f *Fetcher) cachePath(id, year uint) (string, error) {
+	cache := f.Cache
+	if cache == nil {
+		cache = inputs.Cache
+	}
+
+	cDir, err := cache.GetCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cDir, fmt.Sprintf("%d/leaderboard_%d.json", year, id)), nil
+}
+
+// Get returns the leaderboard, only hitting AoC if the cached copy is older than MinRefreshInterval.
+func (f *Fetcher) Get(id, year uint) (*Leaderboard, error) {
+	path, err := f.cachePath(id, year)
+	if err != nil {
+		return nil, err
+	}
+
+	if stat, err := os.Stat(path); err == nil && f.now().Sub(stat.ModTime()) < MinRefreshInterval {
+		return f.GetCached(id, year)
+	}
+
+	buf, err := f.download(id, year)
+	if err != nil {
+		return nil, err
+	}
+
+	var out Leaderboard
+	err = json.Unmarshal(buf, &out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse leaderboard: %w", err)
+	}
+
+	// private leaderboards list member names; keep them to the current user.
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(path, buf, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	// stamp the file with our clock, so the throttle behaves with injected time sources.
+	now := f.now()
+	err = os.Chtimes(path, now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
+// GetCached returns the cached leaderboard without touching the network.
+func (f *Fetcher) GetCached(id, year uint) (*Leaderboard, error) {
+	path, err := f.cachePath(id, year)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var out Leaderboard
+	err = json.Unmarshal(buf, &out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cached leaderboard: %w", err)
+	}
+
+	return &out, nil
+}
+
+func (f *Fetcher) download(id, year uint) ([]byte, error) {
+	baseURL := f.BaseURL
+	if baseURL == "" {
+		baseURL, _ = core.EEnvironmentVariable.BaseURL().Get()
+	}
+
+	targetURL := fmt.Sprintf("%s/%d/leaderboard/private/view/%d.json", strings.TrimSuffix(baseURL, "/"), year, id)
+
+	req, err := http.NewRequest(http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	token, def := core.EEnvironmentVariable.AuthToken().Get()
+	if def {
+		return nil, errors.New("auth token not specified, cannot download leaderboard.")
+	}
+
+	req.AddCookie(&http.Cookie{
+		Name:  "session",
+		Value: token,
+	})
+
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("leaderboard request failed: %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
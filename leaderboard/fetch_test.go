@@ -0,0 +1,124 @@
+package leaderboard
+
+import (
+	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+// fixtureServer serves testdata/leaderboard.json as leaderboard 1 for 2022, counting requests.
+func fixtureServer(t *testing.T, hits *int) *httptest.Server {
+	t.Helper()
+
+	buf, err := os.ReadFile("testdata/leaderboard.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*hits++
+
+		if r.URL.Path != "/2022/leaderboard/private/view/1.json" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if c, err := r.Cookie("session"); err != nil || c.Value != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, _ = w.Write(buf)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestFetcherGet(t *testing.T) {
+	t.Setenv("AOCF_SESSION_COOKIE", "token")
+
+	hits := 0
+	srv := fixtureServer(t, &hits)
+	f := &Fetcher{BaseURL: srv.URL, Client: srv.Client(), Cache: inputs.NewInputCache(t.TempDir())}
+
+	l, err := f.Get(1, 2022)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(l.Members) != 3 || l.Members["1"].Name != "alice" {
+		t.Errorf("unexpected leaderboard: %+v", l)
+	}
+
+	cached, err := f.GetCached(1, 2022)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cached.Members) != 3 {
+		t.Errorf("cached leaderboard has %d members, want 3", len(cached.Members))
+	}
+
+	path, err := f.cachePath(1, 2022)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stat, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stat.Mode().Perm() != 0600 {
+		t.Errorf("cached leaderboard has mode %v, want it readable only by its owner", stat.Mode().Perm())
+	}
+
+	_, err = f.Get(2, 2022)
+	if err == nil {
+		t.Error("expected an error for a missing leaderboard")
+	}
+}
+
+func TestFetcherThrottle(t *testing.T) {
+	t.Setenv("AOCF_SESSION_COOKIE", "token")
+
+	hits := 0
+	srv := fixtureServer(t, &hits)
+
+	now := time.Now()
+	f := &Fetcher{
+		BaseURL: srv.URL,
+		Client:  srv.Client(),
+		Cache:   inputs.NewInputCache(t.TempDir()),
+		Now:     func() time.Time { return now },
+	}
+
+	steps := []struct {
+		after time.Duration
+		hits  int
+	}{
+		{0, 1},
+		{time.Minute, 1},
+		{MinRefreshInterval - time.Second, 1},
+		{MinRefreshInterval, 2}, // the refetch restamps the cache
+		{MinRefreshInterval + time.Minute, 2},
+	}
+
+	start := now
+	for _, step := range steps {
+		now = start.Add(step.after)
+
+		_, err := f.Get(1, 2022)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if hits != step.hits {
+			t.Errorf("after %s: %d requests, want %d", step.after, hits, step.hits)
+		}
+	}
+}
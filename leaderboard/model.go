@@ -0,0 +1,118 @@
+package leaderboard
+
+import (
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Leaderboard mirrors the JSON served by /{year}/leaderboard/private/view/{id}.json
+type Leaderboard struct {
+	OwnerID int                `json:"owner_id"`
+	Event   string             `json:"event"`
+	Members map[string]*Member `json:"members"`
+}
+
+type Member struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Stars       int    `json:"stars"`
+	LocalScore  int    `json:"local_score"`
+	GlobalScore int    `json:"global_score"`
+	LastStarTS  int64  `json:"last_star_ts"`
+
+	// CompletionDayLevel is keyed by day, then by part ("1" or "2").
+	CompletionDayLevel map[string]map[string]Star `json:"completion_day_level"`
+}
+
+type Star struct {
+	GetStarTS int64 `json:"get_star_ts"`
+	StarIndex int64 `json:"star_index"`
+}
+
+func (l *Leaderboard) Year() uint {
+	year, _ := strconv.ParseUint(l.Event, 10, 64)
+	return uint(year)
+}
+
+// Days returns the highest day any member has a star on.
+func (l *Leaderboard) Days() uint {
+	var out uint
+	for _, m := range l.Members {
+		for k := range m.CompletionDayLevel {
+			day, err := strconv.ParseUint(k, 10, 64)
+			if err == nil && uint(day) > out {
+				out = uint(day)
+			}
+		}
+	}
+
+	return out
+}
+
+// Ranked returns the members ordered as AoC displays them: local score, then stars, then earliest last star.
+func (l *Leaderboard) Ranked() []*Member {
+	out := make([]*Member, 0, len(l.Members))
+	for _, m := range l.Members {
+		out = append(out, m)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.LocalScore != b.LocalScore {
+			return a.LocalScore > b.LocalScore
+		}
+		if a.Stars != b.Stars {
+			return a.Stars > b.Stars
+		}
+		if a.LastStarTS != b.LastStarTS {
+			return a.LastStarTS < b.LastStarTS
+		}
+		return a.ID < b.ID
+	})
+
+	return out
+}
+
+func (m *Member) DisplayName() string {
+	if m.Name != "" {
+		return m.Name
+	}
+
+	return "(anonymous user #" + strconv.Itoa(m.ID) + ")"
+}
+
+// StarTime returns when the member obtained the given part of the given day.
+func (m *Member) StarTime(day uint, part int) (time.Time, bool) {
+	parts, ok := m.CompletionDayLevel[strconv.FormatUint(uint64(day), 10)]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	star, ok := parts[strconv.Itoa(part)]
+	if !ok {
+		return time.Time{}, false
+	}
+
+	return time.Unix(star.GetStarTS, 0), true
+}
+
+// StarCount returns how many stars (0-2) the member has on a given day.
+func (m *Member) StarCount(day uint) int {
+	return len(m.CompletionDayLevel[strconv.FormatUint(uint64(day), 10)])
+}
+
+// Delta returns the time between the member's part 1 and part 2 stars on a given day.
+func (m *Member) Delta(day uint) (time.Duration, bool) {
+	p1, ok := m.StarTime(day, 1)
+	if !ok {
+		return 0, false
+	}
+
+	p2, ok := m.StarTime(day, 2)
+	if !ok {
+		return 0, false
+	}
+
+	return p2.Sub(p1), true
+}
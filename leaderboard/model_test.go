@@ -0,0 +1,74 @@
+package leaderboard
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func loadFixture(t *testing.T) *Leaderboard {
+	t.Helper()
+
+	buf, err := os.ReadFile("testdata/leaderboard.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out Leaderboard
+	err = json.Unmarshal(buf, &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &out
+}
+
+func TestLeaderboardModel(t *testing.T) {
+	l := loadFixture(t)
+
+	if l.Year() != 2022 {
+		t.Errorf("Year() = %d, want 2022", l.Year())
+	}
+
+	if l.Days() != 2 {
+		t.Errorf("Days() = %d, want 2", l.Days())
+	}
+
+	// alice and #2 tie on score, so stars break it.
+	want := []string{"alice", "(anonymous user #2)", "carol"}
+	ranked := l.Ranked()
+	if len(ranked) != len(want) {
+		t.Fatalf("Ranked() has %d members, want %d", len(ranked), len(want))
+	}
+	for i, m := range ranked {
+		if m.DisplayName() != want[i] {
+			t.Errorf("Ranked()[%d] = %s, want %s", i, m.DisplayName(), want[i])
+		}
+	}
+}
+
+func TestMemberStars(t *testing.T) {
+	l := loadFixture(t)
+	alice, anon, carol := l.Members["1"], l.Members["2"], l.Members["3"]
+
+	if at, ok := alice.StarTime(1, 2); !ok || !at.Equal(time.Unix(1669871160, 0)) {
+		t.Errorf("alice.StarTime(1, 2) = %s, %t", at, ok)
+	}
+
+	if _, ok := carol.StarTime(1, 2); ok {
+		t.Error("carol has no star on 1/2")
+	}
+
+	if n := anon.StarCount(2); n != 1 {
+		t.Errorf("anon.StarCount(2) = %d, want 1", n)
+	}
+
+	if d, ok := alice.Delta(1); !ok || d != 5*time.Minute {
+		t.Errorf("alice.Delta(1) = %s, %t, want 5m0s", d, ok)
+	}
+
+	if _, ok := carol.Delta(1); ok {
+		t.Error("carol has no delta on day 1")
+	}
+}
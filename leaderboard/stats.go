@@ -0,0 +1,253 @@
+package leaderboard
+
+import (
+	"github.com/Riven-Spell/advent_of_code_forever/calendar"
+	"sort"
+	"time"
+)
+
+// ScoringScheme ranks members by an alternative metric.
+// Schemes where lower is better rank by stars held on the considered days first, so skipping a day never helps.
+type ScoringScheme struct {
+	Name          string
+	LowerIsBetter bool
+	score         func(l *Leaderboard, days []uint) map[int]float64
+}
+
+var ScoringSchemes = []ScoringScheme{
+	EScoringScheme.Local(),
+	EScoringScheme.SolveTime(),
+	EScoringScheme.Stars(),
+}
+
+type eScoringScheme struct{}
+
+var EScoringScheme = &eScoringScheme{}
+
+// Local is AoC's own local score: each star is worth one point per member who got it later (or not at all), plus one.
+func (*eScoringScheme) Local() ScoringScheme {
+	return ScoringScheme{
+		Name: "local",
+		score: func(l *Leaderboard, days []uint) map[int]float64 {
+			out := map[int]float64{}
+			for _, m := range l.Members {
+				out[m.ID] = 0
+			}
+
+			for _, d := range days {
+				for part := 1; part <= 2; part++ {
+					for i, m := range l.finishers(d, part) {
+						out[m.ID] += float64(len(l.Members) - i)
+					}
+				}
+			}
+
+			return out
+		},
+	}
+}
+
+// SolveTime sums the time from unlock to each star, in seconds.
+func (*eScoringScheme) SolveTime() ScoringScheme {
+	return ScoringScheme{
+		Name:          "time",
+		LowerIsBetter: true,
+		score: func(l *Leaderboard, days []uint) map[int]float64 {
+			out := map[int]float64{}
+			for _, m := range l.Members {
+				out[m.ID] = 0
+				for _, d := range days {
+					for part := 1; part <= 2; part++ {
+						if t, ok := m.StarTime(d, part); ok {
+							out[m.ID] += t.Sub(calendar.UnlockTime(l.Year(), d)).Seconds()
+						}
+					}
+				}
+			}
+
+			return out
+		},
+	}
+}
+
+// Stars simply counts stars.
+func (*eScoringScheme) Stars() ScoringScheme {
+	return ScoringScheme{
+		Name: "stars",
+		score: func(l *Leaderboard, days []uint) map[int]float64 {
+			out := map[int]float64{}
+			for _, m := range l.Members {
+				out[m.ID] = float64(m.starsOn(days))
+			}
+
+			return out
+		},
+	}
+}
+
+func ScoringSchemeByName(name string) (ScoringScheme, bool) {
+	for _, s := range ScoringSchemes {
+		if s.Name == name {
+			return s, true
+		}
+	}
+
+	return ScoringScheme{}, false
+}
+
+// finishers returns the members with a given star, earliest first.
+func (l *Leaderboard) finishers(day uint, part int) []*Member {
+	out := make([]*Member, 0)
+	for _, m := range l.Members {
+		if _, ok := m.StarTime(day, part); ok {
+			out = append(out, m)
+		}
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		a, _ := out[i].StarTime(day, part)
+		b, _ := out[j].StarTime(day, part)
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return out[i].ID < out[j].ID
+	})
+
+	return out
+}
+
+func (m *Member) starsOn(days []uint) int {
+	out := 0
+	for _, d := range days {
+		out += m.StarCount(d)
+	}
+
+	return out
+}
+
+func median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+
+	return sorted[mid]
+}
+
+// MemberStats durations are marshalled as nanoseconds, like any time.Duration.
+type MemberStats struct {
+	ID          int                    `json:"id"`
+	Name        string                 `json:"name"`
+	Rank        int                    `json:"rank"`
+	Score       float64                `json:"score"`
+	Stars       int                    `json:"stars"`
+	FirstSolves int                    `json:"first_solves"` // days this member was first to part 2
+	MedianSolve time.Duration          `json:"median_solve"` // unlock to part 2
+	MedianDelta time.Duration          `json:"median_delta"` // part 1 to part 2
+	Deltas      map[uint]time.Duration `json:"deltas"`
+}
+
+type DayStats struct {
+	Day         uint          `json:"day"`
+	FirstPart1  string        `json:"first_part1,omitempty"`
+	FirstPart2  string        `json:"first_part2,omitempty"`
+	MedianDelta time.Duration `json:"median_delta"`
+}
+
+type Stats struct {
+	Scheme  string        `json:"scheme"`
+	Days    []DayStats    `json:"days"`
+	Members []MemberStats `json:"members"`
+}
+
+// Stats computes star-time analytics over every day not listed in ignoreDays.
+func (l *Leaderboard) Stats(scheme ScoringScheme, ignoreDays []uint) Stats {
+	ignored := map[uint]bool{}
+	for _, d := range ignoreDays {
+		ignored[d] = true
+	}
+
+	days := make([]uint, 0)
+	for d := uint(1); d <= l.Days(); d++ {
+		if !ignored[d] {
+			days = append(days, d)
+		}
+	}
+
+	out := Stats{Scheme: scheme.Name, Days: make([]DayStats, 0, len(days))}
+	firstSolves := map[int]int{}
+
+	for _, d := range days {
+		dayStats := DayStats{Day: d}
+		deltas := make([]time.Duration, 0)
+
+		if f := l.finishers(d, 1); len(f) > 0 {
+			dayStats.FirstPart1 = f[0].DisplayName()
+		}
+		if f := l.finishers(d, 2); len(f) > 0 {
+			dayStats.FirstPart2 = f[0].DisplayName()
+			firstSolves[f[0].ID]++
+		}
+
+		for _, m := range l.Members {
+			if delta, ok := m.Delta(d); ok {
+				deltas = append(deltas, delta)
+			}
+		}
+
+		dayStats.MedianDelta = median(deltas)
+		out.Days = append(out.Days, dayStats)
+	}
+
+	scores := scheme.score(l, days)
+	for _, m := range l.Members {
+		memberStats := MemberStats{
+			ID:          m.ID,
+			Name:        m.DisplayName(),
+			Score:       scores[m.ID],
+			Stars:       m.starsOn(days),
+			FirstSolves: firstSolves[m.ID],
+			Deltas:      map[uint]time.Duration{},
+		}
+
+		solves := make([]time.Duration, 0)
+		deltas := make([]time.Duration, 0)
+		for _, d := range days {
+			if delta, ok := m.Delta(d); ok {
+				memberStats.Deltas[d] = delta
+				deltas = append(deltas, delta)
+			}
+			if t, ok := m.StarTime(d, 2); ok {
+				solves = append(solves, t.Sub(calendar.UnlockTime(l.Year(), d)))
+			}
+		}
+
+		memberStats.MedianSolve = median(solves)
+		memberStats.MedianDelta = median(deltas)
+		out.Members = append(out.Members, memberStats)
+	}
+
+	sort.Slice(out.Members, func(i, j int) bool {
+		a, b := out.Members[i], out.Members[j]
+		if scheme.LowerIsBetter && a.Stars != b.Stars {
+			return a.Stars > b.Stars
+		}
+		if a.Score != b.Score {
+			return (a.Score < b.Score) == scheme.LowerIsBetter
+		}
+		return a.ID < b.ID
+	})
+
+	for i := range out.Members {
+		out.Members[i].Rank = i + 1
+	}
+
+	return out
+}
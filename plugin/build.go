@@ -0,0 +1,219 @@
+package plugin
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
+	"github.com/Riven-Spell/advent_of_code_forever/util"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+var runnerTemplate = template.Must(template.New("runner").Parse(`// Code generated by aocf. DO NOT EDIT.
+
+package main
+
+import (
+	"{{.ModulePath}}/plugin"
+
+	_ "{{.Package}}"
+)
+
+func main() {
+	plugin.Serve({{.Day}}, {{.Year}})
+}
+`))
+
+var dayDirRegex = regexp.MustCompile(`^solutions/solution_code/\d+/day\d+$`)
+
+// sourceHash hashes everything a day's runner is built from: the module's Go files, except other days' packages and the importer.
+func sourceHash(root, dayDir string) (string, error) {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "protocol %d\n", ProtocolVersion)
+
+	files := make([]string, 0)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if rel != "." && (strings.HasPrefix(d.Name(), ".") || strings.HasPrefix(d.Name(), "_") || d.Name() == "testdata") {
+				return filepath.SkipDir
+			}
+
+			if dayDirRegex.MatchString(rel) && rel != dayDir {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		// the importer changes with every day created, but runners don't use it.
+		if rel == "solutions/solution_code/importer.go" {
+			return nil
+		}
+
+		if (strings.HasSuffix(rel, ".go") && !strings.HasSuffix(rel, "_test.go")) || rel == "go.mod" || rel == "go.sum" {
+			files = append(files, rel)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	for _, rel := range files {
+		f, err := os.Open(filepath.Join(root, rel))
+		if err != nil {
+			return "", err
+		}
+
+		_, _ = fmt.Fprintf(h, "%s\n", rel)
+		_, err = io.Copy(h, f)
+		_ = f.Close()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:16], nil
+}
+
+// Build compiles the runner for a day out of the solutions repository at root, into binDir.
+// Runners are cached by a hash of their source, so an unchanged day is only ever built once.
+func Build(root, binDir string, day, year uint) (string, error) {
+	dayDir := fmt.Sprintf("solutions/solution_code/%d/day%d", year, day)
+	if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(dayDir))); err != nil {
+		return "", fmt.Errorf("day %d/%d has no code: %w", year, day, err)
+	}
+
+	hash, err := sourceHash(root, dayDir)
+	if err != nil {
+		return "", fmt.Errorf("cannot hash source: %w", err)
+	}
+
+	prefix := fmt.Sprintf("%d-day%d-", year, day)
+	binName := filepath.Join(binDir, prefix+hash)
+	if _, err := os.Stat(binName); err == nil {
+		return binName, nil
+	}
+
+	err = os.MkdirAll(binDir, 0755)
+	if err != nil {
+		return "", err
+	}
+
+	// the runner's main lives outside the repository; go builds it against the module it's run in.
+	mainDir, err := os.MkdirTemp("", "aocf-runner-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(mainDir)
+
+	// the day comes from the project's module, which needn't be aocf's own.
+	projectModule, err := core.ModulePathOf(root)
+	if err != nil {
+		return "", err
+	}
+
+	buf := &bytes.Buffer{}
+	err = runnerTemplate.Execute(buf, map[string]any{
+		"ModulePath": core.ModulePath,
+		"Package":    projectModule + "/" + dayDir,
+		"Day":        day,
+		"Year":       year,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	mainName := filepath.Join(mainDir, "main.go")
+	err = os.WriteFile(mainName, buf.Bytes(), 0644)
+	if err != nil {
+		return "", err
+	}
+
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return "", fmt.Errorf("cannot build runner: %w", err)
+	}
+
+	err = util.ReplaceFile(binName, func(tmpName string) error {
+		build := exec.Command(goBin, "build", "-o", tmpName, mainName)
+		build.Dir = root
+
+		out, err := build.CombinedOutput()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("runner for %d/%d does not build:\n%s", year, day, strings.TrimSpace(string(out)))
+		} else if err != nil {
+			return fmt.Errorf("cannot build runner: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	// runners built from older source are never used again.
+	stale, _ := filepath.Glob(filepath.Join(binDir, prefix+"*"))
+	for _, name := range stale {
+		if name != binName {
+			_ = os.Remove(name)
+		}
+	}
+
+	return binName, nil
+}
+
+// DayRef is a day with code in a solutions repository.
+type DayRef struct {
+	Day, Year uint
+}
+
+// Days lists the days with code under root, by year then day, without needing them linked into aocf.
+func Days(root string) ([]DayRef, error) {
+	dirs, err := filepath.Glob(filepath.Join(root, "solutions", "solution_code", "*", "day*"))
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]DayRef, 0, len(dirs))
+	for _, dir := range dirs {
+		var ref DayRef
+		rel := filepath.ToSlash(strings.TrimPrefix(dir, filepath.Join(root, "solutions", "solution_code")+string(filepath.Separator)))
+		if _, err := fmt.Sscanf(rel, "%d/day%d", &ref.Year, &ref.Day); err != nil || rel != fmt.Sprintf("%d/day%d", ref.Year, ref.Day) {
+			continue
+		}
+		out = append(out, ref)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Year != out[j].Year {
+			return out[i].Year < out[j].Year
+		}
+		return out[i].Day < out[j].Day
+	})
+
+	return out, nil
+}
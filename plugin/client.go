@@ -0,0 +1,171 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Runner is a running day runner, as built by Build.
+type Runner struct {
+	Hello Hello
+
+	bin    string
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	enc    *json.Encoder
+	dec    *json.Decoder
+	cancel context.CancelFunc
+}
+
+// Start launches a runner, and reads its Hello.
+func Start(bin string) (*Runner, error) {
+	r := &Runner{bin: bin}
+	return r, r.start()
+}
+
+func (r *Runner) start() error {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cmd := exec.CommandContext(ctx, r.bin)
+	cmd.Stderr = os.Stderr
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	err = cmd.Start()
+	if err != nil {
+		cancel()
+		return fmt.Errorf("cannot start runner: %w", err)
+	}
+
+	r.cmd, r.stdin, r.cancel = cmd, stdin, cancel
+	r.enc, r.dec = json.NewEncoder(stdin), json.NewDecoder(bufio.NewReader(stdout))
+
+	err = r.dec.Decode(&r.Hello)
+	if err != nil {
+		r.Close()
+		return fmt.Errorf("runner failed to start: %w", err)
+	}
+
+	if r.Hello.Protocol != ProtocolVersion {
+		r.Close()
+		return fmt.Errorf("runner speaks protocol %d, not %d", r.Hello.Protocol, ProtocolVersion)
+	}
+
+	return nil
+}
+
+// Close stops the runner.
+func (r *Runner) Close() {
+	_ = r.stdin.Close()
+	r.cancel()
+	_ = r.cmd.Wait()
+}
+
+// ErrRunnerDied is returned when the runner exits mid-request, e.g. when a solution calls os.Exit or crashes the runtime.
+var ErrRunnerDied = errors.New("runner exited unexpectedly")
+
+// CancelGrace is how long a cancelled request has to reply before its runner is killed.
+const CancelGrace = 2 * time.Second
+
+// request sends a request, and waits for its reply or ctx.
+// If ctx ends first, the runner is asked to cancel the request, and replies just as solutions.RunDay would when cancelled.
+// A runner that doesn't reply within CancelGrace is killed, and restarted for the next request.
+func (r *Runner) request(ctx context.Context, req Request) (Reply, error) {
+	err := r.enc.Encode(req)
+	if err != nil {
+		return Reply{}, ErrRunnerDied
+	}
+
+	replies := make(chan error, 1)
+	var reply Reply
+	go func() {
+		replies <- r.dec.Decode(&reply)
+	}()
+
+	select {
+	case err = <-replies:
+	case <-ctx.Done():
+		err = r.enc.Encode(Request{Op: EOp.Cancel()})
+		if err != nil {
+			return r.restart(ctx, replies)
+		}
+
+		select {
+		case err = <-replies:
+		case <-time.After(CancelGrace):
+			return r.restart(ctx, replies)
+		}
+	}
+
+	if err != nil {
+		return Reply{}, ErrRunnerDied
+	}
+	if reply.Err != "" {
+		return reply, errors.New(reply.Err)
+	}
+	return reply, nil
+}
+
+// restart kills a runner stuck on a cancelled request, and starts a fresh one.
+func (r *Runner) restart(ctx context.Context, replies <-chan error) (Reply, error) {
+	r.Close()
+	<-replies
+	if err := r.start(); err != nil {
+		return Reply{}, fmt.Errorf("%w: cannot restart runner: %s", ctx.Err(), err.Error())
+	}
+	return Reply{}, ctx.Err()
+}
+
+// RunDay runs parts of a variant against an input file, as solutions.RunDay would in process.
+func (r *Runner) RunDay(ctx context.Context, variant, inputPath string, parts []int) solutions.DayResult {
+	reply, err := r.request(ctx, Request{Op: EOp.Run(), Variant: variant, InputPath: inputPath, Parts: parts})
+	if err == nil {
+		out := reply.DayResult()
+
+		// the runner only saw a cancel; report why, as an in-process run would.
+		for i := range out.Parts {
+			if out.Parts[i].Status == solutions.ERunStatus.Cancelled() && ctx.Err() != nil {
+				out.Parts[i].Err = ctx.Err()
+			}
+		}
+
+		return out
+	}
+
+	status := solutions.ERunStatus.Errored()
+	if ctx.Err() != nil {
+		status = solutions.ERunStatus.Cancelled()
+	}
+
+	out := solutions.DayResult{}
+	for _, part := range parts {
+		out.Parts = append(out.Parts, solutions.PartResult{Part: part, Status: status, Err: err})
+	}
+
+	return out
+}
+
+// Generate writes generated input to outputPath, returning its solution if the generator knows it.
+func (r *Runner) Generate(ctx context.Context, complexity uint64, outputPath string) (*inputs.Solution, error) {
+	reply, err := r.request(ctx, Request{Op: EOp.Generate(), Complexity: complexity, OutputPath: outputPath})
+	return reply.Solution, err
+}
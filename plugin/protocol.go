@@ -0,0 +1,118 @@
+package plugin
+
+import (
+	"errors"
+	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions"
+	"time"
+)
+
+// The runner and aocf speak newline-delimited JSON over the runner's stdin and stdout.
+// The runner starts by writing a Hello, then answers each Request with one Reply until stdin closes.
+// A Cancel may be sent while a request is running; it gets no reply of its own, but cancels the running request.
+// Anything the solution prints is sent to stderr instead, so it can't corrupt the protocol.
+
+// ProtocolVersion changes whenever the messages do, so stale runners are rebuilt rather than misread.
+const ProtocolVersion = 1
+
+type eOp struct{}
+
+var EOp = &eOp{}
+
+type Op string
+
+// Run runs parts of a variant against an input file.
+func (*eOp) Run() Op { return "run" }
+
+// Generate writes generated input to a file, replying with its solution if known.
+func (*eOp) Generate() Op { return "generate" }
+
+// Cancel cancels the running request's context.
+func (*eOp) Cancel() Op { return "cancel" }
+
+// Hello describes the day a runner was built for.
+type Hello struct {
+	Protocol  int      `json:"protocol"`
+	Day       uint     `json:"day"`
+	Year      uint     `json:"year"`
+	Variants  []string `json:"variants"`
+	Default   string   `json:"default"` // the variant Index.Get returns
+	Generator bool     `json:"generator"`
+}
+
+type Request struct {
+	Op Op `json:"op"`
+
+	// Run
+	Variant   string `json:"variant,omitempty"`
+	InputPath string `json:"input_path,omitempty"`
+	Parts     []int  `json:"parts,omitempty"`
+
+	// Generate
+	Complexity uint64 `json:"complexity,omitempty"` // 0 for the day's default
+	OutputPath string `json:"output_path,omitempty"`
+}
+
+// PartReply is a solutions.PartResult, with its answer normalized and its error flattened for the wire.
+type PartReply struct {
+	Part     int                 `json:"part"`
+	Status   solutions.RunStatus `json:"status"`
+	Answer   *inputs.Answer      `json:"answer,omitempty"`
+	Err      string              `json:"err,omitempty"`
+	Duration time.Duration       `json:"duration"`
+	Prepare  time.Duration       `json:"prepare,omitempty"`
+}
+
+type Reply struct {
+	Err string `json:"err,omitempty"` // the request itself failed
+
+	// Run
+	Parts        []PartReply   `json:"parts,omitempty"`
+	PreparedOnce bool          `json:"prepared_once,omitempty"`
+	Prepare      time.Duration `json:"prepare,omitempty"`
+
+	// Generate
+	Solution *inputs.Solution `json:"solution,omitempty"`
+}
+
+func toPartReply(r solutions.PartResult) PartReply {
+	out := PartReply{Part: r.Part, Status: r.Status, Duration: r.Duration, Prepare: r.Prepare}
+
+	if answer, ok := inputs.NormalizeAnswer(r.Answer); ok {
+		out.Answer = &answer
+	}
+
+	if r.Err != nil {
+		out.Err = r.Err.Error()
+	}
+
+	return out
+}
+
+func (p PartReply) toPartResult() solutions.PartResult {
+	out := solutions.PartResult{Part: p.Part, Status: p.Status, Duration: p.Duration, Prepare: p.Prepare}
+
+	if p.Answer != nil {
+		out.Answer = *p.Answer
+	}
+
+	switch {
+	case p.Status == solutions.ERunStatus.NotImplemented():
+		out.Err = solutions.ErrNotImplemented
+	case p.Err != "":
+		out.Err = errors.New(p.Err)
+	}
+
+	return out
+}
+
+// DayResult converts a run's reply back into the result solutions.RunDay would have given.
+func (r Reply) DayResult() solutions.DayResult {
+	out := solutions.DayResult{PreparedOnce: r.PreparedOnce, Prepare: r.Prepare}
+
+	for _, p := range r.Parts {
+		out.Parts = append(out.Parts, p.toPartResult())
+	}
+
+	return out
+}
@@ -0,0 +1,159 @@
+package plugin
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/solutions"
+	"io"
+	"os"
+)
+
+// Serve is the runner's main loop: it answers requests for one day, which must be registered, until stdin closes.
+func Serve(day, year uint) {
+	// keep stdout for the protocol, and send whatever the solution prints to stderr.
+	protocol := os.Stdout
+	os.Stdout = os.Stderr
+
+	err := serve(day, year, os.Stdin, protocol)
+	if err != nil {
+		_, _ = fmt.Fprintf(os.Stderr, "runner for %d/%d: %s\n", year, day, err.Error())
+		os.Exit(1)
+	}
+}
+
+func hello(day, year uint) (Hello, error) {
+	if errs := solutions.Index.RegistrationErrors(); len(errs) != 0 {
+		return Hello{}, errs[0]
+	}
+
+	if !solutions.Index.Has(day, year) {
+		return Hello{}, fmt.Errorf("day %d/%d is not registered", year, day)
+	}
+
+	out := Hello{Protocol: ProtocolVersion, Day: day, Year: year, Variants: solutions.Index.Variants(day, year)}
+	for _, v := range out.Variants {
+		if solutions.Index.GetVariant(day, year, v) == solutions.Index.Get(day, year) {
+			out.Default = v
+		}
+	}
+
+	d := solutions.Index.Get(day, year)
+	out.Generator = d.HasGenerator()
+
+	return out, nil
+}
+
+func serve(day, year uint, in io.Reader, out io.Writer) error {
+	h, err := hello(day, year)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	err = enc.Encode(h)
+	if err != nil {
+		return err
+	}
+
+	// requests are read in the background, so a cancel can arrive while a request runs.
+	requests := make(chan Request)
+	readErr := make(chan error, 1)
+	go func() {
+		dec := json.NewDecoder(bufio.NewReader(in))
+		for {
+			var req Request
+			if err := dec.Decode(&req); err != nil {
+				readErr <- err
+				close(requests)
+				return
+			}
+			requests <- req
+		}
+	}()
+
+	for req := range requests {
+		if req.Op == EOp.Cancel() {
+			continue // nothing is running
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		replies := make(chan Reply, 1)
+		go func() {
+			switch req.Op {
+			case EOp.Run():
+				replies <- run(ctx, day, year, req)
+			case EOp.Generate():
+				replies <- generate(day, year, req)
+			default:
+				replies <- Reply{Err: fmt.Sprintf("unknown op '%s'", req.Op)}
+			}
+		}()
+
+		var reply Reply
+		for waiting := true; waiting; {
+			select {
+			case reply = <-replies:
+				waiting = false
+			case next, ok := <-requests:
+				if !ok || next.Op == EOp.Cancel() {
+					cancel()
+				}
+				if !ok {
+					requests = nil // stdin closed; finish up, then stop
+				}
+			}
+		}
+		cancel()
+
+		err = enc.Encode(reply)
+		if err != nil {
+			return err
+		}
+
+		if requests == nil {
+			break
+		}
+	}
+
+	if err := <-readErr; err != io.EOF {
+		return fmt.Errorf("bad request: %w", err)
+	}
+
+	return nil
+}
+
+func run(ctx context.Context, day, year uint, req Request) Reply {
+	d := solutions.Index.GetVariant(day, year, req.Variant)
+	if d == nil {
+		return Reply{Err: fmt.Sprintf("day %d/%d has no variant '%s'", year, day, req.Variant)}
+	}
+
+	input := solutions.StreamInput(func() (io.ReadCloser, error) {
+		return os.Open(req.InputPath)
+	})
+
+	result := solutions.RunDay(ctx, d, input, req.Parts)
+
+	reply := Reply{PreparedOnce: result.PreparedOnce, Prepare: result.Prepare}
+	for _, p := range result.Parts {
+		reply.Parts = append(reply.Parts, toPartReply(p))
+	}
+
+	return reply
+}
+
+func generate(day, year uint, req Request) Reply {
+	d := solutions.Index.Get(day, year)
+	if !d.HasGenerator() {
+		return Reply{Err: fmt.Sprintf("day %d/%d does not contain an input generator", year, day)}
+	}
+
+	solution, err := solutions.GenerateTo(d, req.Complexity, req.OutputPath)
+	if err != nil {
+		return Reply{Err: err.Error()}
+	}
+
+	return Reply{Solution: solution}
+}
@@ -1,51 +1,210 @@
 package solutions
 
-import "github.com/Riven-Spell/advent_of_code_forever/inputs"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/calendar"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
+	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
 
 // InputGenerator generates input with a given complexity (number of elements to compute).
 // It is intended for benchmarking, testing, and generating giga inputs.
 type InputGenerator func(complexity uint64) (input string, solution *inputs.Solution)
 
-// Solution is an interface that exposes a simple structure:
+// Solution is implemented by every registered day, as a StreamingSolution, ContextSolution or LegacySolution.
+// The runner detects which through a type assertion; registration rejects anything else (see ValidateSolution).
+type Solution interface{}
+
+// ValidateSolution checks that a Solution implements one of the interfaces the runner accepts.
+func ValidateSolution(s Solution) error {
+	switch s.(type) {
+	case StreamingSolution, ContextSolution, LegacySolution:
+		return nil
+	case nil:
+		return errors.New("solution is nil")
+	default:
+		return fmt.Errorf("%T implements none of StreamingSolution, ContextSolution or LegacySolution", s)
+	}
+}
+
+// ContextSolution exposes a simple structure:
 // The runner should call Prepare() on it to prepare the input
 // Then call the part 1 and part 2 functions if wanted.
-type Solution interface {
+// Parts that aren't written yet should return ErrNotImplemented.
+type ContextSolution interface {
+	Prepare(ctx context.Context, input string) error
+	Part1(ctx context.Context) (any, error) // compared via inputs.AnswersEqual
+	Part2(ctx context.Context) (any, error) // compared via inputs.AnswersEqual
+}
+
+// LegacySolution is the original Solution interface, with no errors or cancellation.
+// A nil answer is treated as not implemented, and panics are reported as errors.
+type LegacySolution interface {
 	Prepare(input string)
-	Part1() any // runtime.DeepEquals is used for comparisons
-	Part2() any // runtime.DeepEquals is used for comparisons
+	Part1() any // compared via inputs.AnswersEqual
+	Part2() any // compared via inputs.AnswersEqual
 }
 
 type Year struct {
-	Days    [25]*Day
-	Year    uint
-	LastDay uint
+	Days     []*Day            // sized by calendar.DaysIn. Holds the default variant of each day.
+	Variants []map[string]*Day // every variant of each day, keyed by name
+	Year     uint
+	LastDay  uint
 }
 
 type Day struct {
-	Solution          Solution
-	Generator         InputGenerator // not mandatory for solution but mandatory for benchmarking
+	// New creates a fresh Solution, so no state leaks between runs. Preferred over Solution.
+	New func() Solution
+	// Solution is a single shared instance, reused for every run. Kept for days registered before New existed.
+	Solution Solution
+
+	Generator         InputGenerator          // not mandatory for solution but mandatory for benchmarking
+	StreamGenerator   StreamingInputGenerator // preferred over Generator when present
 	DefaultComplexity uint64
+
+	Meta *Metadata // optional, shown by `aocf list`
+}
+
+// Metadata describes a day for browsing. Every field is optional.
+type Metadata struct {
+	Title           string   `json:"title,omitempty"`
+	Tags            []string `json:"tags,omitempty"` // e.g. "graph", "dp", "grid"
+	Author          string   `json:"author,omitempty"`
+	Notes           string   `json:"notes,omitempty"`
+	ComplexityClass string   `json:"complexity_class,omitempty"` // expected complexity, e.g. "O(n log n)"
+}
+
+func (m *Metadata) HasTag(tag string) bool {
+	if m == nil {
+		return false
+	}
+
+	for _, t := range m.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Instance returns the Solution to run: a fresh one from New if possible, otherwise the shared Solution.
+func (d *Day) Instance() Solution {
+	if d.New != nil {
+		return d.New()
+	}
+
+	return d.Solution
 }
 
+// DefaultVariant is the name Insert registers days under.
+const DefaultVariant = "default"
+
 type SolutionIndex struct {
+	lock     sync.RWMutex
 	years    map[uint]*Year
 	lastYear uint
+
+	sources map[dayKey]string // registering package of every variant
+	errors  []error
+}
+
+type dayKey struct {
+	year, day uint
+	variant   string
+}
+
+// RegistrationError describes a day that was registered incorrectly, and so was not (re-)registered.
+type RegistrationError struct {
+	Year, Day uint
+	Variant   string
+	Package   string // the registering package
+	Reason    string
+}
+
+func (e RegistrationError) Error() string {
+	return fmt.Sprintf("day %d/%d (variant %s, registered by %s): %s", e.Year, e.Day, e.Variant, e.Package, e.Reason)
+}
+
+// callerPackage returns the import path of the first package up the stack that isn't this one.
+func callerPackage() string {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+
+	for {
+		frame, more := frames.Next()
+
+		// Function names look like <import path>.<func>, and the import path's last element can't contain a dot.
+		pkg := frame.Function
+		slash := strings.LastIndex(pkg, "/")
+		if dot := strings.Index(pkg[slash+1:], "."); dot >= 0 {
+			pkg = pkg[:slash+1+dot]
+		}
+
+		if pkg != core.ModulePath+"/solutions" {
+			return pkg
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
+
+// ExpectedPackage returns the import path a day must be registered from, in the solution_code at codePath.
+func ExpectedPackage(codePath string, day, year uint) string {
+	return fmt.Sprintf("%s/%d/day%d", codePath, year, day)
+}
+
+// IndexedDay is a registered day, along with where it is registered.
+type IndexedDay struct {
+	Year, Number uint
+	*Day
 }
 
 var Index = &SolutionIndex{
 	years: map[uint]*Year{
-		2015: {
+		calendar.FirstYear: {
 			LastDay: 0, // This is invalid but create will fix it
 		},
 	},
-	lastYear: 2015,
+	lastYear: calendar.FirstYear,
+	sources:  map[dayKey]string{},
+}
+
+// RegistrationErrors returns every problem found while days were registered, in registration order.
+func (i *SolutionIndex) RegistrationErrors() []error {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return append([]error{}, i.errors...)
+}
+
+// Source returns the import path of the package that registered a variant of a day, if known.
+func (i *SolutionIndex) Source(day, year uint, variant string) string {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.sources[dayKey{year: year, day: day, variant: variant}]
 }
 
 func (i *SolutionIndex) GetCurrentDay() (day, year uint) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
 	return i.years[i.lastYear].LastDay, i.lastYear
 }
 
 func (i *SolutionIndex) GetCurrentDayForYear(year uint) uint {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
 	targetYear, ok := i.years[year]
 	if ok {
 		return targetYear.LastDay
@@ -54,16 +213,190 @@ func (i *SolutionIndex) GetCurrentDayForYear(year uint) uint {
 	}
 }
 
-func (i *SolutionIndex) Get(day, year uint) *Day {
+func (i *SolutionIndex) get(day, year uint) *Day {
 	targetYear, ok := i.years[year]
-	if !ok || day < 1 || day > 25 {
+	if !ok || day < 1 || day > uint(len(targetYear.Days)) {
 		return nil
 	}
 
 	return targetYear.Days[day-1]
 }
 
+func (i *SolutionIndex) Get(day, year uint) *Day {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.get(day, year)
+}
+
+func (i *SolutionIndex) Has(day, year uint) bool {
+	return i.Get(day, year) != nil
+}
+
+// HasYear reports whether any day of the year is registered.
+func (i *SolutionIndex) HasYear(year uint) bool {
+	return len(i.DaysOf(year)) != 0
+}
+
+// Years returns every year with at least one registered day, in order.
+func (i *SolutionIndex) Years() []uint {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	out := make([]uint, 0, len(i.years))
+	for year, targetYear := range i.years {
+		for _, d := range targetYear.Days {
+			if d != nil {
+				out = append(out, year)
+				break
+			}
+		}
+	}
+
+	sort.Slice(out, func(a, b int) bool { return out[a] < out[b] })
+	return out
+}
+
+// DaysOf returns the registered days of a year, in order.
+func (i *SolutionIndex) DaysOf(year uint) []uint {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	out := make([]uint, 0)
+	if targetYear, ok := i.years[year]; ok {
+		for d, day := range targetYear.Days {
+			if day != nil {
+				out = append(out, uint(d+1))
+			}
+		}
+	}
+
+	return out
+}
+
+// Gaps returns the days of a year before its last registered day that aren't registered.
+func (i *SolutionIndex) Gaps(year uint) []uint {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	out := make([]uint, 0)
+	if targetYear, ok := i.years[year]; ok {
+		for d := uint(1); d < targetYear.LastDay; d++ {
+			if i.get(d, year) == nil {
+				out = append(out, d)
+			}
+		}
+	}
+
+	return out
+}
+
+// All returns every registered day (default variants), ordered by year then day.
+func (i *SolutionIndex) All() []IndexedDay {
+	out := make([]IndexedDay, 0)
+	for _, year := range i.Years() {
+		for _, day := range i.DaysOf(year) {
+			out = append(out, IndexedDay{Year: year, Number: day, Day: i.Get(day, year)})
+		}
+	}
+
+	return out
+}
+
+// GetVariant returns a named implementation of a day, or nil.
+func (i *SolutionIndex) GetVariant(day, year uint, variant string) *Day {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	targetYear, ok := i.years[year]
+	if !ok || day < 1 || day > uint(len(targetYear.Variants)) {
+		return nil
+	}
+
+	return targetYear.Variants[day-1][variant]
+}
+
+// Variants returns the names of every implementation of a day, sorted with the default first.
+func (i *SolutionIndex) Variants(day, year uint) []string {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	targetYear, ok := i.years[year]
+	if !ok || day < 1 || day > uint(len(targetYear.Variants)) {
+		return nil
+	}
+
+	out := make([]string, 0, len(targetYear.Variants[day-1]))
+	for name := range targetYear.Variants[day-1] {
+		out = append(out, name)
+	}
+
+	sort.Slice(out, func(a, b int) bool {
+		if (out[a] == DefaultVariant) != (out[b] == DefaultVariant) {
+			return out[a] == DefaultVariant
+		}
+		return out[a] < out[b]
+	})
+
+	return out
+}
+
+// Insert registers the default variant of a day.
 func (i *SolutionIndex) Insert(day, year uint, solution *Day) {
+	i.InsertVariant(day, year, DefaultVariant, solution)
+}
+
+// InsertVariant registers one of several competing implementations of a day under a name (e.g. "naive", "bitset").
+// Get returns the DefaultVariant, or whichever variant was registered first if there is none.
+// It is safe to call concurrently.
+// Invalid registrations (out-of-range days, duplicates, solutions of the wrong type,
+// or days registered outside of solution_code/<year>/day<num>) are not inserted,
+// and are instead collected for RegistrationErrors.
+func (i *SolutionIndex) InsertVariant(day, year uint, variant string, solution *Day) {
+	pkg := callerPackage()
+
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	key := dayKey{year: year, day: day, variant: variant}
+	fail := func(reason string) {
+		i.errors = append(i.errors, RegistrationError{Year: year, Day: day, Variant: variant, Package: pkg, Reason: reason})
+	}
+
+	if err := calendar.ValidateDay(day, year); err != nil {
+		fail(err.Error())
+		return
+	}
+
+	if solution == nil || (solution.New == nil && solution.Solution == nil) {
+		fail("no Solution or New was provided")
+		return
+	}
+
+	// New is called once here, so a factory returning the wrong type fails at startup rather than mid-run.
+	instance := solution.Solution
+	if solution.New != nil {
+		instance = solution.New()
+	}
+
+	if err := ValidateSolution(instance); err != nil {
+		fail(err.Error())
+		return
+	}
+
+	if prev, ok := i.sources[key]; ok {
+		fail("already registered by " + prev)
+		return
+	}
+
+	// the index is filled before anything knows the project root, so the registering package says which module it's in.
+	if codePath, ok := core.SolutionCodePathFor(pkg); ok && pkg != ExpectedPackage(codePath, day, year) {
+		fail("must be registered from " + ExpectedPackage(codePath, day, year))
+		return
+	}
+
+	i.sources[key] = pkg
+
 	if year > i.lastYear {
 		i.lastYear = year
 	}
@@ -71,17 +404,28 @@ func (i *SolutionIndex) Insert(day, year uint, solution *Day) {
 	targetYear, ok := i.years[year]
 	if !ok {
 		targetYear = &Year{
-			Days:    [25]*Day{},
 			Year:    year,
 			LastDay: day,
 		}
 		i.years[year] = targetYear
-		ok = true
+	}
+
+	// seeded years (2015) start without days.
+	if targetYear.Days == nil {
+		targetYear.Days = make([]*Day, calendar.DaysIn(year))
+		targetYear.Variants = make([]map[string]*Day, calendar.DaysIn(year))
 	}
 
 	if day > targetYear.LastDay {
 		targetYear.LastDay = day
 	}
 
-	targetYear.Days[day-1] = solution
+	if targetYear.Variants[day-1] == nil {
+		targetYear.Variants[day-1] = map[string]*Day{}
+	}
+	targetYear.Variants[day-1][variant] = solution
+
+	if variant == DefaultVariant || targetYear.Days[day-1] == nil {
+		targetYear.Days[day-1] = solution
+	}
 }
@@ -0,0 +1,110 @@
+package solutions
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"io"
+	"os"
+	"strings"
+)
+
+// StreamingSolution is an optional alternative to Prepare for inputs too large to hold in a single string.
+// If a solution implements it, the runner calls PrepareStream instead of Prepare.
+type StreamingSolution interface {
+	PrepareStream(ctx context.Context, input io.Reader) error
+	Part1(ctx context.Context) (any, error)
+	Part2(ctx context.Context) (any, error)
+}
+
+// StreamingInputGenerator is an InputGenerator that writes the input out as it goes, rather than building a string.
+type StreamingInputGenerator func(complexity uint64, w io.Writer) (solution *inputs.Solution, err error)
+
+// Input is a day's input, either held in memory or opened on demand (e.g. streamed from disk).
+type Input struct {
+	text string
+	open func() (io.ReadCloser, error)
+}
+
+func StringInput(input string) Input {
+	return Input{text: input}
+}
+
+// StreamInput defers to open every time the input is read, so it is never held in memory unless asked for as a string.
+func StreamInput(open func() (io.ReadCloser, error)) Input {
+	return Input{open: open}
+}
+
+func (i Input) Open() (io.ReadCloser, error) {
+	if i.open != nil {
+		return i.open()
+	}
+
+	return io.NopCloser(strings.NewReader(i.text)), nil
+}
+
+// String reads the entire input into memory.
+func (i Input) String() (string, error) {
+	if i.open == nil {
+		return i.text, nil
+	}
+
+	r, err := i.open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	buf, err := io.ReadAll(r)
+	return string(buf), err
+}
+
+// HasGenerator reports whether the day can generate input, through either Generator or StreamGenerator.
+func (d *Day) HasGenerator() bool {
+	return d.Generator != nil || d.StreamGenerator != nil
+}
+
+// Generate writes generated input to w, at the day's DefaultComplexity if complexity is 0.
+// It returns the input's solution, if the generator knows it.
+func (d *Day) Generate(complexity uint64, w io.Writer) (*inputs.Solution, error) {
+	if !d.HasGenerator() {
+		return nil, errors.New("no generator present")
+	}
+
+	if complexity == 0 {
+		complexity = d.DefaultComplexity
+	}
+
+	if d.StreamGenerator != nil {
+		return d.StreamGenerator(complexity, w)
+	}
+
+	text, solution := d.Generator(complexity)
+	_, err := io.Copy(w, strings.NewReader(text))
+	return solution, err
+}
+
+// GenerateTo writes a day's generated input to a file, as Generate does. The file is removed if generating fails.
+func GenerateTo(day *Day, complexity uint64, path string) (*inputs.Solution, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := bufio.NewWriter(f)
+	solution, err := day.Generate(complexity, w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = os.Remove(path)
+		return nil, err
+	}
+
+	return solution, nil
+}
@@ -0,0 +1,276 @@
+package solutions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/util"
+	"time"
+)
+
+// ErrNotImplemented is returned by a part that hasn't been written yet.
+var ErrNotImplemented = errors.New("not implemented")
+
+type RunStatus uint8
+
+type eRunStatus struct{}
+
+var ERunStatus = &eRunStatus{}
+
+func (*eRunStatus) NotImplemented() RunStatus { return 0 }
+func (*eRunStatus) Errored() RunStatus        { return 1 }
+func (*eRunStatus) Cancelled() RunStatus      { return 2 }
+func (*eRunStatus) Answered() RunStatus       { return 3 }
+
+func (s RunStatus) String() string {
+	switch s {
+	case ERunStatus.NotImplemented():
+		return "not implemented"
+	case ERunStatus.Errored():
+		return "errored"
+	case ERunStatus.Cancelled():
+		return "cancelled"
+	case ERunStatus.Answered():
+		return "answered"
+	default:
+		return fmt.Sprintf("RunStatus(%d)", uint8(s))
+	}
+}
+
+type PartResult struct {
+	Part     int
+	Status   RunStatus
+	Answer   any
+	Err      error
+	Duration time.Duration // time spent in the part itself, excluding Prepare
+	Prepare  time.Duration // time spent in this part's own Prepare, if it had one
+}
+
+// legacyAdapter lets a LegacySolution be run as a ContextSolution.
+// A nil answer means not implemented, and panics become errors.
+// Legacy solutions can't observe cancellation, so they are abandoned in the background instead.
+type legacyAdapter struct {
+	s LegacySolution
+}
+
+type legacyResult struct {
+	out any
+	err error
+}
+
+func (a legacyAdapter) call(ctx context.Context, f func() any) (any, error) {
+	// buffered, so an abandoned call can still finish without anyone listening.
+	done := make(chan legacyResult, 1)
+
+	go func() {
+		var res legacyResult
+		defer func() {
+			if r := recover(); r != nil {
+				res.err = fmt.Errorf("panic: %v", r)
+			}
+			done <- res
+		}()
+
+		res.out = f()
+	}()
+
+	select {
+	case res := <-done:
+		if res.err == nil && res.out == nil {
+			res.err = ErrNotImplemented
+		}
+		return res.out, res.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (a legacyAdapter) Prepare(ctx context.Context, input string) error {
+	_, err := a.call(ctx, func() any {
+		a.s.Prepare(input)
+		return struct{}{}
+	})
+	return err
+}
+
+func (a legacyAdapter) Part1(ctx context.Context) (any, error) {
+	return a.call(ctx, a.s.Part1)
+}
+
+func (a legacyAdapter) Part2(ctx context.Context) (any, error) {
+	return a.call(ctx, a.s.Part2)
+}
+
+// AsContextSolution detects which interface a solution implements, adapting legacy solutions.
+func AsContextSolution(s Solution) (ContextSolution, error) {
+	switch sol := s.(type) {
+	case ContextSolution:
+		return sol, nil
+	case LegacySolution:
+		return legacyAdapter{s: sol}, nil
+	default:
+		return nil, fmt.Errorf("%T implements neither ContextSolution nor LegacySolution", s)
+	}
+}
+
+func classify(ctx context.Context, answer any, err error) (RunStatus, any, error) {
+	switch {
+	case err == nil && answer == nil, errors.Is(err, ErrNotImplemented):
+		return ERunStatus.NotImplemented(), nil, nil
+	case ctx.Err() != nil && (err == nil || errors.Is(err, ctx.Err())):
+		return ERunStatus.Cancelled(), nil, ctx.Err()
+	case err != nil:
+		return ERunStatus.Errored(), nil, err
+	default:
+		return ERunStatus.Answered(), answer, nil
+	}
+}
+
+// SharedPreparation is optional. Solutions implementing it declare whether Part1 mutates the state Prepare built.
+// If it doesn't, the runner prepares once and runs both parts on the same instance.
+// If it does, the runner prepares once and runs Part1 on a Clone, when the solution is a Cloner.
+type SharedPreparation interface {
+	Part1MutatesState() bool
+}
+
+// Cloner is optional, and lets the runner copy prepared state rather than preparing twice.
+type Cloner interface {
+	Clone() Solution
+}
+
+type DayResult struct {
+	Parts []PartResult
+	// PreparedOnce is set when both parts shared a single Prepare, which Prepare then times.
+	PreparedOnce bool
+	Prepare      time.Duration
+}
+
+func prepareStream(ctx context.Context, sol StreamingSolution, input Input) error {
+	r, err := input.Open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	return sol.PrepareStream(ctx, r)
+}
+
+// prepare hands StreamingSolutions a reader, and everything else the input as a string.
+func prepare(ctx context.Context, s Solution, input Input) (time.Duration, error) {
+	startTime := time.Now()
+
+	if sol, ok := s.(StreamingSolution); ok {
+		err := prepareStream(ctx, sol, input)
+		return time.Since(startTime), err
+	}
+
+	sol, err := AsContextSolution(s)
+	if err != nil {
+		return 0, err
+	}
+
+	text, err := input.String()
+	if err != nil {
+		return 0, err
+	}
+
+	startTime = time.Now() // don't count reading the input
+	err = sol.Prepare(ctx, text)
+	return time.Since(startTime), err
+}
+
+func runPart(ctx context.Context, s Solution, part int) PartResult {
+	result := PartResult{Part: part}
+
+	var run func(ctx context.Context) (any, error)
+	if sol, ok := s.(StreamingSolution); ok {
+		run = util.Ternary(part == 2, sol.Part2, sol.Part1)
+	} else {
+		sol, err := AsContextSolution(s)
+		if err != nil {
+			result.Status, result.Err = ERunStatus.Errored(), err
+			return result
+		}
+		run = util.Ternary(part == 2, sol.Part2, sol.Part1)
+	}
+
+	startTime := time.Now() // time the run
+	answer, err := run(ctx)
+	result.Duration = time.Since(startTime)
+	result.Status, result.Answer, result.Err = classify(ctx, answer, err)
+
+	return result
+}
+
+func prepareFailed(ctx context.Context, part int, err error) PartResult {
+	result := PartResult{Part: part}
+	result.Status, _, result.Err = classify(ctx, nil, err)
+	if result.Status == ERunStatus.Errored() {
+		result.Err = fmt.Errorf("prepare: %w", err)
+	}
+
+	return result
+}
+
+// RunPart prepares the solution with the input, then runs a single part of it.
+func RunPart(ctx context.Context, s Solution, input Input, part int) PartResult {
+	prepareTime, err := prepare(ctx, s, input)
+	if err != nil {
+		return prepareFailed(ctx, part, err)
+	}
+
+	result := runPart(ctx, s, part)
+	result.Prepare = prepareTime
+	return result
+}
+
+// RunDay runs the requested parts of a day, sharing a single Prepare between them when the solution allows it.
+// Otherwise, each part gets a fresh instance and its own Prepare.
+func RunDay(ctx context.Context, day *Day, input Input, parts []int) DayResult {
+	out := DayResult{}
+
+	s := day.Instance()
+	sharer, ok := s.(SharedPreparation)
+	_, cloneable := s.(Cloner)
+	if !ok || len(parts) < 2 || (sharer.Part1MutatesState() && !cloneable) {
+		for i, part := range parts {
+			if i != 0 {
+				s = day.Instance()
+			}
+			out.Parts = append(out.Parts, RunPart(ctx, s, input, part))
+		}
+
+		return out
+	}
+
+	out.PreparedOnce = true
+	prepareTime, err := prepare(ctx, s, input)
+	out.Prepare = prepareTime
+	if err != nil {
+		for _, part := range parts {
+			out.Parts = append(out.Parts, prepareFailed(ctx, part, err))
+		}
+
+		return out
+	}
+
+	abandoned := false
+	for _, part := range parts {
+		if abandoned {
+			// a cancelled part may still be running against s, so start over on a fresh instance.
+			out.Parts = append(out.Parts, RunPart(ctx, day.Instance(), input, part))
+			continue
+		}
+
+		target := s
+		if part == 1 && sharer.Part1MutatesState() {
+			target = s.(Cloner).Clone() // part 2 gets the untouched original
+		}
+
+		result := runPart(ctx, target, part)
+		abandoned = result.Status == ERunStatus.Cancelled()
+		out.Parts = append(out.Parts, result)
+	}
+
+	return out
+}
@@ -4,5 +4,5 @@ package solution_code
 This code is automatically generated. Do not modify it by hand.
 It will re-generate every time `aocf create` is ran.
 solution_code is indexed by <year>/day<num>.
- */
-
+Only packages that register days with solutions.Index are imported.
+*/
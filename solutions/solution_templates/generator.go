@@ -0,0 +1,268 @@
+package solution_templates
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultGeneratorComplexity is used when there's no cached input to size a generator from.
+const DefaultGeneratorComplexity = 1000
+
+// maxPalette is how many characters a generated grid samples from, weighted by how often they appear.
+const maxPalette = 32
+
+type eGeneratorKind struct{}
+
+var EGeneratorKind = &eGeneratorKind{}
+
+// GeneratorKind is the input shape a scaffolded generator produces.
+type GeneratorKind string
+
+func (*eGeneratorKind) Unknown() GeneratorKind { return "" }
+
+// IntLines is lines of Separator-separated integers, one line per unit of complexity.
+func (*eGeneratorKind) IntLines() GeneratorKind { return "int_lines" }
+
+// IntList is a single line of Separator-separated integers, one integer per unit of complexity.
+func (*eGeneratorKind) IntList() GeneratorKind { return "int_list" }
+
+// Grid is rows of Width characters drawn from Palette, one row per unit of complexity.
+func (*eGeneratorKind) Grid() GeneratorKind { return "grid" }
+
+// GeneratorInfill describes the starter generator scaffolded by `aocf create --with-generator`.
+type GeneratorInfill struct {
+	Kind              GeneratorKind
+	DefaultComplexity uint64
+	Description       string // e.g. "1000 lines of 2 space-separated integers in [1, 99999]"
+
+	// IntLines and IntList
+	Separator            string
+	MinFields, MaxFields int
+	Min, Max             int64
+	Span                 int64 // Max - Min + 1, so templates don't need arithmetic
+
+	// Grid
+	Width   int
+	Palette string
+}
+
+// inputStats accumulates what the cached input looks like, one line at a time.
+type inputStats struct {
+	lines int
+
+	separator      string
+	ints           bool
+	padded         bool // some integer had a leading zero, as in a grid of digits
+	fields         int  // total integers seen
+	minFields      int
+	maxFields      int
+	minInt, maxInt int64
+
+	grid   bool
+	width  int
+	counts map[byte]int
+
+	records bool // every line is "key: value"
+	blocks  bool // blank lines separate blocks of lines
+}
+
+// splitInts parses a line of integers, also reporting whether any was zero-padded (e.g. "007").
+func splitInts(line, separator string) (values []int64, padded, ok bool) {
+	var fields []string
+	if separator == " " {
+		fields = strings.Fields(line)
+	} else {
+		fields = strings.Split(line, separator)
+	}
+
+	values = make([]int64, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		v, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return nil, false, false
+		}
+		values = append(values, v)
+
+		digits := strings.TrimLeft(f, "+-")
+		padded = padded || (len(digits) > 1 && digits[0] == '0')
+	}
+
+	return values, padded, len(values) != 0
+}
+
+// gridOfChars reports whether the input is best read as a character grid. Equal-width lines of plain integers
+// (e.g. "199\n200\n208") are a list of numbers rather than a grid of digits, unless some are zero-padded.
+func (s *inputStats) gridOfChars() bool {
+	return s.lines > 1 && s.grid && (!s.ints || s.padded)
+}
+
+func (s *inputStats) add(line string) {
+	if s.lines == 0 {
+		s.separator = " "
+		if strings.Contains(line, ",") {
+			s.separator = ","
+		}
+		s.ints = true
+		s.grid = len(line) > 1 && !strings.ContainsAny(line, " \t,")
+		s.width = len(line)
+		s.minFields = -1
+		s.counts = map[byte]int{}
+		s.records = true
+	}
+	s.lines++
+
+	if s.ints {
+		values, padded, ok := splitInts(line, s.separator)
+		s.ints = ok
+		s.padded = s.padded || padded
+
+		for _, v := range values {
+			if s.fields == 0 || v < s.minInt {
+				s.minInt = v
+			}
+			if s.fields == 0 || v > s.maxInt {
+				s.maxInt = v
+			}
+			s.fields++
+		}
+
+		if s.minFields == -1 || len(values) < s.minFields {
+			s.minFields = len(values)
+		}
+		if len(values) > s.maxFields {
+			s.maxFields = len(values)
+		}
+	}
+
+	s.records = s.records && strings.Contains(line, ": ")
+
+	if s.grid {
+		s.grid = len(line) == s.width && !strings.ContainsAny(line, " \t,")
+		for i := 0; i < len(line); i++ {
+			s.counts[line[i]]++
+		}
+	}
+}
+
+// palette lists the grid's characters, repeated roughly in proportion to how often they appear,
+// so that rare characters (start and end markers, say) stay rare.
+func (s *inputStats) palette() string {
+	chars := make([]byte, 0, len(s.counts))
+	total := 0
+	for c, n := range s.counts {
+		chars = append(chars, c)
+		total += n
+	}
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+
+	out := &strings.Builder{}
+	for _, c := range chars {
+		n := s.counts[c] * maxPalette / total
+		if n == 0 {
+			n = 1
+		}
+		out.WriteString(strings.Repeat(string(c), n))
+	}
+
+	return out.String()
+}
+
+// intRange is the range generated integers are drawn from: the cached input's, narrowed if needed so that
+// max - min + 1 fits in rng.Int63n.
+func (s *inputStats) intRange() (lo, hi, span int64) {
+	lo, hi = s.minInt, s.maxInt
+	if hi-lo+1 <= 0 { // overflowed int64
+		hi = lo + (math.MaxInt64 - 1)
+	}
+
+	return lo, hi, hi - lo + 1
+}
+
+func (s *inputStats) generator() *GeneratorInfill {
+	sepName := map[string]string{" ": "space", ",": "comma"}[s.separator]
+	lo, hi, span := s.intRange()
+
+	switch {
+	case s.gridOfChars():
+		return &GeneratorInfill{
+			Kind:              EGeneratorKind.Grid(),
+			DefaultComplexity: uint64(s.lines),
+			Description:       strconv.Itoa(s.lines) + " rows of a " + strconv.Itoa(s.width) + "-wide character grid",
+			Width:             s.width,
+			Palette:           s.palette(),
+		}
+	case s.lines == 1 && s.ints && s.fields > 1:
+		return &GeneratorInfill{
+			Kind:              EGeneratorKind.IntList(),
+			DefaultComplexity: uint64(s.fields),
+			Description: "a single line of " + strconv.Itoa(s.fields) + " " + sepName + "-separated integers in [" +
+				strconv.FormatInt(lo, 10) + ", " + strconv.FormatInt(hi, 10) + "]",
+			Separator: s.separator,
+			Min:       lo,
+			Max:       hi,
+			Span:      span,
+		}
+	case s.lines > 0 && s.ints:
+		fields := strconv.Itoa(s.minFields)
+		if s.minFields != s.maxFields {
+			fields += "-" + strconv.Itoa(s.maxFields)
+		}
+
+		return &GeneratorInfill{
+			Kind:              EGeneratorKind.IntLines(),
+			DefaultComplexity: uint64(s.lines),
+			Description: strconv.Itoa(s.lines) + " lines of " + fields + " " + sepName + "-separated integers in [" +
+				strconv.FormatInt(lo, 10) + ", " + strconv.FormatInt(hi, 10) + "]",
+			Separator: s.separator,
+			MinFields: s.minFields,
+			MaxFields: s.maxFields,
+			Min:       lo,
+			Max:       hi,
+			Span:      span,
+		}
+	default:
+		return nil
+	}
+}
+
+// analyzeInput reads an input through, collecting what its lines look like.
+func analyzeInput(r io.Reader) (*inputStats, error) {
+	stats := &inputStats{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	blank := false
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" {
+			blank = true
+			continue
+		}
+
+		stats.add(line)
+		if blank && stats.lines > 1 {
+			// blank-line separated blocks are beyond a starter generator.
+			stats.ints, stats.grid, stats.records = false, false, false
+			stats.blocks = true
+		}
+		blank = false
+	}
+
+	return stats, scanner.Err()
+}
+
+// InferGenerator reads an input through, and describes a generator producing input of the same shape.
+// Inputs it can't make sense of (blank-line separated blocks, free text...) get an empty skeleton.
+func InferGenerator(r io.Reader) *GeneratorInfill {
+	stats, err := analyzeInput(r)
+	if gen := stats.generator(); err == nil && gen != nil {
+		return gen
+	}
+
+	return &GeneratorInfill{Kind: EGeneratorKind.Unknown(), DefaultComplexity: DefaultGeneratorComplexity}
+}
@@ -0,0 +1,98 @@
+package solution_templates
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestInfer(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		gen    GeneratorInfill // only the fields set here are checked, besides Kind
+		parser ParserKind
+	}{
+		{
+			name:   "equal-width integers are numbers",
+			input:  "199\n200\n208\n210\n",
+			gen:    GeneratorInfill{Kind: EGeneratorKind.IntLines(), Min: 199, Max: 210, Span: 12},
+			parser: EParserKind.Ints(),
+		},
+		{
+			name:   "zero-padded digits are a grid",
+			input:  "00100\n11110\n10110\n",
+			gen:    GeneratorInfill{Kind: EGeneratorKind.Grid(), Width: 5},
+			parser: EParserKind.Grid(),
+		},
+		{
+			name:   "characters are a grid",
+			input:  "#.#\n.#.\n#.#\n",
+			gen:    GeneratorInfill{Kind: EGeneratorKind.Grid(), Width: 3},
+			parser: EParserKind.Grid(),
+		},
+		{
+			name:   "one line of integers",
+			input:  "3,-1,4\n",
+			gen:    GeneratorInfill{Kind: EGeneratorKind.IntList(), Separator: ",", Min: -1, Max: 4, Span: 6},
+			parser: EParserKind.Ints(),
+		},
+		{
+			name:   "rows of integers",
+			input:  "1 2\n3 4 5\n",
+			gen:    GeneratorInfill{Kind: EGeneratorKind.IntLines(), Separator: " ", MinFields: 2, MaxFields: 3, Min: 1, Max: 5, Span: 5},
+			parser: EParserKind.IntRows(),
+		},
+		{
+			name:   "span of all of int64 is narrowed",
+			input:  "-9223372036854775808\n9223372036854775807\n",
+			gen:    GeneratorInfill{Kind: EGeneratorKind.IntLines(), Min: math.MinInt64, Max: -2, Span: math.MaxInt64},
+			parser: EParserKind.Ints(),
+		},
+		{
+			name:   "span just past int64 is narrowed",
+			input:  "0\n9223372036854775807\n",
+			gen:    GeneratorInfill{Kind: EGeneratorKind.IntLines(), Min: 0, Max: math.MaxInt64 - 1, Span: math.MaxInt64},
+			parser: EParserKind.Ints(),
+		},
+		{
+			name:   "records",
+			input:  "a: 1\nb: 2\n",
+			gen:    GeneratorInfill{Kind: EGeneratorKind.Unknown()},
+			parser: EParserKind.Records(),
+		},
+		{
+			name:   "blocks",
+			input:  "1\n2\n\n3\n",
+			gen:    GeneratorInfill{Kind: EGeneratorKind.Unknown()},
+			parser: EParserKind.Blocks(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := InferGenerator(strings.NewReader(tt.input))
+			want := tt.gen
+			if want.Kind != gen.Kind ||
+				(want.Separator != "" && want.Separator != gen.Separator) ||
+				(want.MinFields != 0 && (want.MinFields != gen.MinFields || want.MaxFields != gen.MaxFields)) ||
+				(want.Span != 0 && (want.Min != gen.Min || want.Max != gen.Max || want.Span != gen.Span)) ||
+				want.Width != gen.Width {
+				t.Errorf("InferGenerator = %+v, want %+v", *gen, want)
+			}
+
+			if gen.Span < 0 {
+				t.Errorf("Span %d overflowed", gen.Span)
+			}
+
+			parser := InferParser(strings.NewReader(tt.input))
+			if parser == nil || parser.Kind != tt.parser {
+				t.Errorf("InferParser = %+v, want %s", parser, tt.parser)
+			}
+		})
+	}
+
+	if parser := InferParser(strings.NewReader("")); parser != nil {
+		t.Errorf("InferParser of an empty input = %+v, want nil", parser)
+	}
+}
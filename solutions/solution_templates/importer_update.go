@@ -1,61 +1,156 @@
 package solution_templates
 
 import (
+	"bytes"
 	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
+	"github.com/Riven-Spell/advent_of_code_forever/util"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"io/fs"
-	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 )
 
-// UpdateImporter regenerates the importer.
-// It should never be run if the working directory is not the root of advent_of_code_forever.
-func UpdateImporter() error {
-	workDir, err := os.Getwd() // wd is assumed to be code dir.
-	if err != nil {
-		if err != nil {
-			return fmt.Errorf("failed to get pwd: %w\n", err)
+// registrationCalls finds a parsed file's calls to solutions.Index.Insert and solutions.Index.InsertVariant,
+// honoring whatever name the solutions package is imported under.
+func registrationCalls(file *ast.File) []*ast.CallExpr {
+	solutionsName := ""
+	for _, imp := range file.Imports {
+		path, _ := strconv.Unquote(imp.Path.Value)
+		if path != core.ModulePath+"/solutions" {
+			continue
 		}
-	}
 
-	solutionsPackage := filepath.Join(workDir, "solutions/solution_code")
+		solutionsName = "solutions"
+		if imp.Name != nil {
+			solutionsName = imp.Name.Name
+		}
+	}
 
-	importerName := filepath.Join(workDir, "solutions/solution_code/importer.go")
-	f, err := os.OpenFile(importerName, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0755)
-	if err != nil {
-		return fmt.Errorf("cannot open file %s: %w\n", importerName, err)
+	if solutionsName == "" || solutionsName == "_" {
+		return nil
 	}
 
-	includeSet := map[string]bool{}
+	calls := make([]*ast.CallExpr, 0)
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		// solutions.Index.Insert(...)
+		method, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || (method.Sel.Name != "Insert" && method.Sel.Name != "InsertVariant") {
+			return true
+		}
+
+		index, ok := method.X.(*ast.SelectorExpr)
+		if !ok || index.Sel.Name != "Index" {
+			return true
+		}
+
+		if pkg, ok := index.X.(*ast.Ident); ok && pkg.Name == solutionsName {
+			calls = append(calls, call)
+		}
+		return true
+	})
 
-	err = filepath.WalkDir(
+	return calls
+}
+
+// registersDays reports whether a parsed file registers any days.
+func registersDays(file *ast.File) bool {
+	return len(registrationCalls(file)) != 0
+}
+
+// GenerateImporter builds the importer for a solution_code directory, whose import path is codePath,
+// importing only packages that register days.
+// Shared packages (<year>/shared/<name>) are skipped.
+// The output is sorted and gofmt'd, so it only changes when the set of days does.
+func GenerateImporter(solutionsPackage, codePath string) ([]byte, error) {
+	registering := map[string]bool{}
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir(
 		solutionsPackage,
 		func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
 				return err
 			}
 
-			if !d.IsDir() && strings.HasSuffix(d.Name(), ".go") {
-				if dir := filepath.Dir(path); dir != solutionsPackage {
-					pkgStartIndex := strings.Index(dir, "solution_code/")
-					dir = strings.TrimPrefix(dir[pkgStartIndex:], "solution_code/")
-					includeSet[dir] = true
-				}
+			if d.IsDir() || !strings.HasSuffix(d.Name(), ".go") || strings.HasSuffix(d.Name(), "_test.go") {
+				return nil
+			}
+
+			dir := filepath.Dir(path)
+			if dir == solutionsPackage || registering[dir] {
+				return nil
+			}
+
+			// shared packages are imported by the days using them, never by the importer.
+			if rel, err := filepath.Rel(solutionsPackage, dir); err == nil && IsSharedPackage(rel) {
+				return nil
+			}
+
+			file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+			if err != nil {
+				return fmt.Errorf("cannot parse %s: %w", path, err)
+			}
+
+			if registersDays(file) {
+				registering[dir] = true
 			}
 
 			return nil
 		})
+	if err != nil {
+		return nil, err
+	}
+
+	includes := make([]string, 0, len(registering))
+	for dir := range registering {
+		rel, err := filepath.Rel(solutionsPackage, dir)
+		if err != nil {
+			return nil, err
+		}
+		includes = append(includes, filepath.ToSlash(rel))
+	}
+	sort.Strings(includes)
+
+	buf := &bytes.Buffer{}
+	err = ImporterTemplate.Execute(buf, ImporterTemplateInfill{CodePath: codePath, Imports: includes})
+	if err != nil {
+		return nil, err
+	}
 
-	includes := make([]string, 0)
+	return format.Source(buf.Bytes())
+}
 
-	for k := range includeSet {
-		includes = append(includes, k)
+// UpdateImporter regenerates the importer under workDir, the root of the solutions repository.
+// If generation fails, the existing importer is left untouched.
+func UpdateImporter(workDir string) error {
+	solutionsPackage := filepath.Join(workDir, "solutions/solution_code")
+	importerName := filepath.Join(solutionsPackage, "importer.go")
+
+	codePath, err := core.SolutionCodePathOf(workDir)
+	if err != nil {
+		return fmt.Errorf("not updating importer: %w", err)
+	}
+
+	code, err := GenerateImporter(solutionsPackage, codePath)
+	if err != nil {
+		return fmt.Errorf("not updating importer: %w", err)
 	}
 
-	err = ImporterTemplate.Execute(f, ImporterTemplateInfill{Imports: includes})
+	err = util.WriteFileAtomic(importerName, code, 0644)
 	if err != nil {
-		return err
+		return fmt.Errorf("cannot write %s: %w", importerName, err)
 	}
 
-	return f.Close()
+	return nil
 }
@@ -0,0 +1,117 @@
+package solution_templates
+
+import (
+	"bufio"
+	"github.com/Riven-Spell/advent_of_code_forever/inputs"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	previewLines = 5
+	previewWidth = 80
+)
+
+var puzzleTitleRegex = regexp.MustCompile(`--- Day \d+: (.+?) ---`)
+
+// puzzleTitle pulls the title out of a cached puzzle's "--- Day N: Title ---" header.
+func puzzleTitle(puzzle string) string {
+	match := puzzleTitleRegex.FindStringSubmatch(puzzle)
+	if match == nil {
+		return ""
+	}
+
+	return match[1]
+}
+
+func allInts(fields []string) bool {
+	if len(fields) == 0 {
+		return false
+	}
+
+	for _, f := range fields {
+		if _, err := strconv.ParseInt(strings.TrimSpace(f), 10, 64); err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// guessLineFormat describes what a line of input looks like, for a comment in the template.
+func guessLineFormat(line string) string {
+	switch {
+	case allInts([]string{line}):
+		return "a single integer"
+	case strings.Contains(line, ",") && allInts(strings.Split(line, ",")):
+		return "comma-separated integers"
+	case allInts(strings.Fields(line)):
+		return "space-separated integers"
+	case strings.Trim(line, ".#") == "":
+		return "a grid row of '.' and '#'"
+	case strings.Contains(line, ": "):
+		return "a key: value record"
+	case !strings.ContainsAny(line, " \t"):
+		return "a single token (possibly a grid row)"
+	default:
+		return "free text"
+	}
+}
+
+// FillFromCache adds the puzzle title, an input preview and a parser to the infill, if the puzzle or input are cached.
+func (infill *SolutionTemplateInfill) FillFromCache(cache *inputs.InputCache) {
+	if puzzle, err := cache.GetPuzzle(infill.Day, infill.Year); err == nil {
+		infill.PuzzleTitle = puzzleTitle(puzzle)
+	}
+
+	if !cache.HasCachedInput(infill.Day, infill.Year) {
+		return
+	}
+
+	r, err := cache.OpenInput(infill.Day, infill.Year)
+	if err != nil {
+		return
+	}
+	defer r.Close()
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for len(infill.InputPreview) < previewLines && scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if len(infill.InputPreview) == 0 {
+			infill.InputFormat = guessLineFormat(line)
+		}
+
+		if len(line) > previewWidth {
+			line = line[:previewWidth] + "..."
+		}
+		infill.InputPreview = append(infill.InputPreview, line)
+	}
+
+	// the parser needs the whole input, so start over.
+	full, err := cache.OpenInput(infill.Day, infill.Year)
+	if err != nil {
+		return
+	}
+	defer full.Close()
+
+	infill.Parser = InferParser(full)
+}
+
+// AddGenerator asks for a starter input generator, shaped like the cached input if there is one.
+func (infill *SolutionTemplateInfill) AddGenerator(cache *inputs.InputCache) {
+	infill.Generator = &GeneratorInfill{Kind: EGeneratorKind.Unknown(), DefaultComplexity: DefaultGeneratorComplexity}
+
+	if !cache.HasCachedInput(infill.Day, infill.Year) {
+		return
+	}
+
+	r, err := cache.OpenInput(infill.Day, infill.Year)
+	if err != nil {
+		return
+	}
+	defer r.Close()
+
+	infill.Generator = InferGenerator(r)
+}
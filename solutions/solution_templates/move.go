@@ -0,0 +1,238 @@
+package solution_templates
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DayMove describes renaming a day's package, e.g. from 2022/day5 to 2022/day6.
+type DayMove struct {
+	FromDay, FromYear uint
+	ToDay, ToYear     uint
+
+	CodePath string // import path of the project's solution_code; core.SolutionCodePath if empty
+}
+
+// FromPackage and ToPackage are import paths relative to solution_code, e.g. "2022/day5".
+func (m DayMove) FromPackage() string { return fmt.Sprintf("%d/day%d", m.FromYear, m.FromDay) }
+func (m DayMove) ToPackage() string   { return fmt.Sprintf("%d/day%d", m.ToYear, m.ToDay) }
+
+// quotedPath is the import path of a package relative to solution_code, as it appears in source.
+func (m DayMove) quotedPath(pkg string) string {
+	codePath := m.CodePath
+	if codePath == "" {
+		codePath = core.SolutionCodePath
+	}
+
+	return strconv.Quote(codePath + "/" + pkg)
+}
+
+// rename maps old identifiers onto new ones: package names (dayN, dayN_test) and Day<N>-prefixed type names.
+func (m DayMove) rename(name string) string {
+	fromPkg, toPkg := fmt.Sprintf("day%d", m.FromDay), fmt.Sprintf("day%d", m.ToDay)
+	if name == fromPkg || name == fromPkg+"_test" {
+		return toPkg + strings.TrimPrefix(name, fromPkg)
+	}
+
+	typePrefix := regexp.MustCompile(fmt.Sprintf(`^Day%d(\D|$)`, m.FromDay))
+	if typePrefix.MatchString(name) {
+		return fmt.Sprintf("Day%d", m.ToDay) + strings.TrimPrefix(name, fmt.Sprintf("Day%d", m.FromDay))
+	}
+
+	return name
+}
+
+// rewriteLiteral replaces an integer literal equal to from with to, reporting whether it did.
+func rewriteLiteral(expr ast.Expr, from, to uint) bool {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.INT || from == to {
+		return false
+	}
+
+	v, err := strconv.ParseUint(lit.Value, 0, 64)
+	if err != nil || uint(v) != from {
+		return false
+	}
+
+	lit.Value = strconv.FormatUint(uint64(to), 10)
+	return true
+}
+
+// rewriteFile applies a move to one file: its package clause, identifiers, registrations, and imports of the moved package.
+// It reports whether anything changed.
+func (m DayMove) rewriteFile(file *ast.File, inMovedPackage bool) bool {
+	changed := false
+	fromPath := m.quotedPath(m.FromPackage())
+	toPath := m.quotedPath(m.ToPackage())
+
+	// the names the moved package is referred to by in this file.
+	localNames := map[string]bool{}
+	for _, imp := range file.Imports {
+		if imp.Path.Value != fromPath {
+			continue
+		}
+
+		imp.Path.Value = toPath
+		changed = true
+
+		if imp.Name != nil {
+			localNames[imp.Name.Name] = true
+		} else {
+			localNames[fmt.Sprintf("day%d", m.FromDay)] = true
+		}
+	}
+
+	if !inMovedPackage {
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			if pkg, ok := sel.X.(*ast.Ident); ok && localNames[pkg.Name] {
+				pkg.Name = m.rename(pkg.Name)
+				sel.Sel.Name = m.rename(sel.Sel.Name)
+			}
+			return true
+		})
+
+		return changed
+	}
+
+	ast.Inspect(file, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			if renamed := m.rename(ident.Name); renamed != ident.Name {
+				ident.Name = renamed
+				changed = true
+			}
+		}
+		return true
+	})
+
+	for _, call := range registrationCalls(file) {
+		if len(call.Args) < 2 {
+			continue
+		}
+
+		if rewriteLiteral(call.Args[0], m.FromDay, m.ToDay) {
+			changed = true
+		}
+		if rewriteLiteral(call.Args[1], m.FromYear, m.ToYear) {
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+// FilesAffectedByMove lists the Go files under solutionsPackage that import the moved package, besides the moved package itself.
+func FilesAffectedByMove(solutionsPackage string, m DayMove) ([]string, error) {
+	fromPath := m.quotedPath(m.FromPackage())
+	movedDir := filepath.Join(solutionsPackage, filepath.FromSlash(m.FromPackage()))
+	fset := token.NewFileSet()
+
+	out := make([]string, 0)
+	err := filepath.WalkDir(solutionsPackage, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".go") || filepath.Dir(path) == movedDir {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, parser.ImportsOnly)
+		if err != nil {
+			return fmt.Errorf("cannot parse %s: %w", path, err)
+		}
+
+		for _, imp := range file.Imports {
+			if imp.Path.Value == fromPath {
+				out = append(out, path)
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return out, err
+}
+
+// RewriteMovedDay rewrites Go source for a move: every file in movedDir (the package, already at its new location),
+// and the given files that import it. Files are parsed before any is written, so a parse error changes nothing.
+func RewriteMovedDay(movedDir string, importers []string, m DayMove) error {
+	type parsed struct {
+		path  string
+		file  *ast.File
+		moved bool
+	}
+
+	fset := token.NewFileSet()
+	files := make([]parsed, 0)
+
+	entries, err := os.ReadDir(movedDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") {
+			files = append(files, parsed{path: filepath.Join(movedDir, entry.Name()), moved: true})
+		}
+	}
+
+	for _, path := range importers {
+		files = append(files, parsed{path: path})
+	}
+
+	for i := range files {
+		files[i].file, err = parser.ParseFile(fset, files[i].path, nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			return fmt.Errorf("cannot parse %s: %w", files[i].path, err)
+		}
+	}
+
+	for _, f := range files {
+		if f.moved {
+			// day5.go and day5_test.go follow the package name.
+			fromPkg := fmt.Sprintf("day%d", m.FromDay)
+			base := filepath.Base(f.path)
+			if strings.HasPrefix(base, fromPkg+".") || strings.HasPrefix(base, fromPkg+"_") {
+				renamed := filepath.Join(movedDir, fmt.Sprintf("day%d", m.ToDay)+strings.TrimPrefix(base, fromPkg))
+				if err := os.Rename(f.path, renamed); err != nil {
+					return fmt.Errorf("cannot rename %s: %w", f.path, err)
+				}
+				f.path = renamed
+			}
+		}
+
+		if !m.rewriteFile(f.file, f.moved) {
+			continue
+		}
+
+		buf := &bytes.Buffer{}
+		err = format.Node(buf, fset, f.file)
+		if err != nil {
+			return fmt.Errorf("cannot format %s: %w", f.path, err)
+		}
+
+		err = os.WriteFile(f.path, buf.Bytes(), 0644)
+		if err != nil {
+			return fmt.Errorf("cannot write %s: %w", f.path, err)
+		}
+	}
+
+	return nil
+}
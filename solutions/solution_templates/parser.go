@@ -0,0 +1,83 @@
+package solution_templates
+
+import (
+	"io"
+	"strconv"
+)
+
+type eParserKind struct{}
+
+var EParserKind = &eParserKind{}
+
+// ParserKind is the input format a scaffolded Prepare parses.
+type ParserKind string
+
+// Grid is rows of characters of equal width, parsed into [][]byte.
+func (*eParserKind) Grid() ParserKind { return "grid" }
+
+// Ints is one integer per line, or a single line of Separator-separated integers, parsed into []int.
+func (*eParserKind) Ints() ParserKind { return "ints" }
+
+// IntRows is lines of several Separator-separated integers, parsed into [][]int.
+func (*eParserKind) IntRows() ParserKind { return "int_rows" }
+
+// Records is "key: value" lines, parsed into key/value pairs.
+func (*eParserKind) Records() ParserKind { return "records" }
+
+// Blocks is groups of lines separated by blank lines, parsed into [][]string.
+func (*eParserKind) Blocks() ParserKind { return "blocks" }
+
+// Lines is anything else, split into lines.
+func (*eParserKind) Lines() ParserKind { return "lines" }
+
+// ParserInfill describes the parsing code scaffolded into Prepare.
+type ParserInfill struct {
+	Kind        ParserKind
+	Description string // e.g. "a 140x140 character grid"
+	Separator   string // Ints and IntRows; " " means any whitespace
+}
+
+func (s *inputStats) parser() *ParserInfill {
+	sepName := map[string]string{" ": "space", ",": "comma"}[s.separator]
+
+	switch {
+	case s.lines == 0:
+		return nil
+	case s.blocks:
+		return &ParserInfill{Kind: EParserKind.Blocks(), Description: "blocks of lines, separated by blank lines"}
+	case s.gridOfChars():
+		return &ParserInfill{
+			Kind:        EParserKind.Grid(),
+			Description: "a " + strconv.Itoa(s.width) + "x" + strconv.Itoa(s.lines) + " character grid",
+		}
+	case s.ints && s.maxFields == 1:
+		return &ParserInfill{Kind: EParserKind.Ints(), Description: "one integer per line", Separator: " "}
+	case s.ints && s.lines == 1:
+		return &ParserInfill{
+			Kind:        EParserKind.Ints(),
+			Description: "a single line of " + sepName + "-separated integers",
+			Separator:   s.separator,
+		}
+	case s.ints:
+		return &ParserInfill{
+			Kind:        EParserKind.IntRows(),
+			Description: "lines of " + sepName + "-separated integers",
+			Separator:   s.separator,
+		}
+	case s.records:
+		return &ParserInfill{Kind: EParserKind.Records(), Description: `"key: value" records, one per line`}
+	default:
+		return &ParserInfill{Kind: EParserKind.Lines(), Description: "lines of free text"}
+	}
+}
+
+// InferParser reads an input through, and describes the parsing code Prepare should start with.
+// It returns nil for an empty input.
+func InferParser(r io.Reader) *ParserInfill {
+	stats, err := analyzeInput(r)
+	if err != nil {
+		return nil
+	}
+
+	return stats.parser()
+}
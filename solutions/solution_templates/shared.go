@@ -0,0 +1,107 @@
+package solution_templates
+
+import (
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/core"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SharedDir is the directory under a year holding packages shared between its days.
+const SharedDir = "shared"
+
+var sharedPackageRegex = regexp.MustCompile(`^\d+/` + SharedDir + `/[^/]+$`)
+
+// IsSharedPackage reports whether a path relative to solution_code (e.g. "2019/shared/intcode") is a shared package.
+func IsSharedPackage(rel string) bool {
+	return sharedPackageRegex.MatchString(filepath.ToSlash(rel))
+}
+
+// SharedPackageDir is where a year's shared package lives.
+func SharedPackageDir(workDir string, year uint, name string) string {
+	return filepath.Join(workDir, "solutions/solution_code", fmt.Sprint(year), SharedDir, name)
+}
+
+// SharedImport is a shared package imported by a day.
+type SharedImport struct {
+	Name string
+	Path string
+	Use  string // a declaration using one of its exported names, so the import compiles; empty if it exports nothing
+}
+
+// firstExported finds a declaration of an exported name in a parsed file, as a blank declaration using it.
+func firstExported(pkg string, file *ast.File) string {
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil && d.Name.IsExported() && d.Type.TypeParams == nil {
+				return fmt.Sprintf("var _ = %s.%s", pkg, d.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if s.Name.IsExported() && s.TypeParams == nil {
+						return fmt.Sprintf("var _ *%s.%s", pkg, s.Name.Name)
+					}
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						if name.IsExported() {
+							return fmt.Sprintf("var _ = %s.%s", pkg, name.Name)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// FindShared looks up a year's shared package, for a day to import.
+func FindShared(workDir string, year uint, name string) (SharedImport, error) {
+	codePath, err := core.SolutionCodePathOf(workDir)
+	if err != nil {
+		return SharedImport{}, err
+	}
+
+	dir := SharedPackageDir(workDir, year, name)
+	out := SharedImport{Name: name, Path: fmt.Sprintf("%s/%d/%s/%s", codePath, year, SharedDir, name)}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return out, fmt.Errorf("no shared package %s for %d (create it with `aocf create --shared %s`): %w", name, year, name, err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") && !strings.HasSuffix(entry.Name(), "_test.go") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	fset := token.NewFileSet()
+	for _, fileName := range names {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, fileName), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return out, fmt.Errorf("cannot parse %s: %w", fileName, err)
+		}
+
+		if file.Name.Name != name {
+			return out, fmt.Errorf("shared package %s is declared as package %s", name, file.Name.Name)
+		}
+
+		if out.Use == "" {
+			out.Use = firstExported(name, file)
+		}
+	}
+
+	return out, nil
+}
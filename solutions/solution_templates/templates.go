@@ -1,9 +1,14 @@
 package solution_templates
 
 import (
+	"bytes"
 	"embed"
+	"errors"
 	"fmt"
+	"go/format"
+	"os"
 	"path/filepath"
+	"sort"
 	"text/template"
 )
 
@@ -28,14 +33,144 @@ func prepareTemplate(tName string) *template.Template {
 
 var SolutionTemplate = prepareTemplate("solution.go.template")
 
+// DefaultSolutionTemplate is the name of the embedded solution template.
+const DefaultSolutionTemplate = "solution"
+
+var (
+	SharedTemplate     = prepareTemplate("shared.go.template")
+	SharedTestTemplate = prepareTemplate("shared_test.go.template")
+)
+
+// SharedTemplateName and SharedTestTemplateName are the templates `aocf create --shared` scaffolds from.
+const (
+	SharedTemplateName     = "shared"
+	SharedTestTemplateName = "shared_test"
+)
+
+// embeddedTemplates are the templates LoadSolutionTemplate falls back on, by name.
+var embeddedTemplates = map[string]*template.Template{
+	DefaultSolutionTemplate: SolutionTemplate,
+	SharedTemplateName:      SharedTemplate,
+	SharedTestTemplateName:  SharedTestTemplate,
+}
+
+// SharedTemplateInfill fills the templates for a shared package, solution_code/<year>/shared/<name>.
+type SharedTemplateInfill struct {
+	Package  string
+	Year     uint
+	TestName string // Package, capitalized
+}
+
 type SolutionTemplateInfill struct {
-	Package string
-	Day     uint
-	Year    uint
+	Package  string
+	Day      uint
+	Year     uint
+	FinalDay bool // the final day of an event only has one part
+
+	// These are only filled if the puzzle or input are cached.
+	PuzzleTitle  string
+	InputPreview []string // the first few lines of input, truncated
+	InputFormat  string   // a guess at the first line's format, e.g. "comma-separated integers"
+	Parser       *ParserInfill
+
+	// Generator is only set for `aocf create --with-generator`.
+	Generator *GeneratorInfill
+
+	// Shared are the year's shared packages to import, from `aocf create --use`.
+	Shared []SharedImport
+}
+
+// StdImports lists the standard library packages the embedded template's code needs, sorted.
+func (infill SolutionTemplateInfill) StdImports() []string {
+	imports := map[string]bool{"context": true}
+
+	if p := infill.Parser; p != nil {
+		imports["strings"] = true
+		if p.Kind == EParserKind.Ints() || p.Kind == EParserKind.IntRows() {
+			imports["fmt"] = true
+			imports["strconv"] = true
+		}
+	}
+
+	if g := infill.Generator; g != nil && g.Kind != EGeneratorKind.Unknown() {
+		imports["math/rand"] = true
+		imports["strings"] = true
+		if g.Kind == EGeneratorKind.IntLines() || g.Kind == EGeneratorKind.IntList() {
+			imports["strconv"] = true
+		}
+	}
+
+	out := make([]string, 0, len(imports))
+	for imp := range imports {
+		out = append(out, imp)
+	}
+	sort.Strings(out)
+
+	return out
+}
+
+// TemplateDirs returns where override templates are looked for, in order of preference:
+// .aocf/templates in the repository, then aocf/templates in the user's config directory.
+func TemplateDirs(workDir string) []string {
+	out := []string{filepath.Join(workDir, ".aocf", "templates")}
+
+	if configDir, err := os.UserConfigDir(); err == nil {
+		out = append(out, filepath.Join(configDir, "aocf", "templates"))
+	}
+
+	return out
+}
+
+// LoadSolutionTemplate finds the named template (<name>.go.template) in TemplateDirs, falling back on the embedded ones.
+func LoadSolutionTemplate(workDir, name string) (*template.Template, error) {
+	fileName := name + ".go.template"
+
+	for _, dir := range TemplateDirs(workDir) {
+		buf, err := os.ReadFile(filepath.Join(dir, fileName))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed opening %s: %w", filepath.Join(dir, fileName), err)
+		}
+
+		out, err := template.New(fileName).Parse(string(buf))
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing %s: %w", filepath.Join(dir, fileName), err)
+		}
+
+		return out, nil
+	}
+
+	if embedded, ok := embeddedTemplates[name]; ok {
+		return embedded, nil
+	}
+
+	return nil, fmt.Errorf("no template named '%s' in %v", name, TemplateDirs(workDir))
+}
+
+// RenderSolution fills a solution (or shared package) template, and checks the result is gofmt-clean Go.
+func RenderSolution(t *template.Template, infill any) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	err := t.Execute(buf, infill)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fill template: %w", err)
+	}
+
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("template %s does not produce valid Go: %w", t.Name(), err)
+	}
+
+	if !bytes.Equal(formatted, buf.Bytes()) {
+		return nil, fmt.Errorf("template %s does not produce gofmt-clean Go; run its output through gofmt and update the template", t.Name())
+	}
+
+	return buf.Bytes(), nil
 }
 
 var ImporterTemplate = prepareTemplate("importer.go.template")
 
 type ImporterTemplateInfill struct {
-	Imports []string
+	CodePath string   // import path of the project's solution_code
+	Imports  []string // relative to CodePath, e.g. "2022/day5"
 }
@@ -0,0 +1,39 @@
+package solution_templates
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// BuildError carries the compiler's diagnostics when generated code doesn't build.
+type BuildError struct {
+	Diagnostics string
+}
+
+func (e *BuildError) Error() string {
+	return "solution code does not build:\n" + e.Diagnostics
+}
+
+// VerifyBuild type-checks the solution code by building the importer, which pulls in every registered day.
+// It is run with the go toolchain, since that is the one thing we know the user has.
+func VerifyBuild(workDir string) error {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return fmt.Errorf("cannot verify build: %w", err)
+	}
+
+	build := exec.Command(goBin, "build", "./solutions/solution_code/...")
+	build.Dir = workDir
+
+	out, err := build.CombinedOutput()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &BuildError{Diagnostics: strings.TrimSpace(string(out))}
+	} else if err != nil {
+		return fmt.Errorf("cannot verify build: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,371 @@
+package transaction
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/Riven-Spell/advent_of_code_forever/util"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxJournal is how many committed transactions are kept in the trash for Undo.
+const MaxJournal = 20
+
+// Transaction groups filesystem changes so they can be rolled back together, or undone later once committed.
+// Every path must be tracked before it is changed, created or removed.
+type Transaction struct {
+	trashDir string
+	record   Record
+	tracked  map[string]bool
+}
+
+// Record is a committed transaction, as stored in the trash's journal.
+type Record struct {
+	ID          string    `json:"id"`
+	Description string    `json:"description"`
+	Time        time.Time `json:"time"`
+	Entries     []Entry   `json:"entries"`
+}
+
+// Entry is a path changed by a transaction.
+type Entry struct {
+	Path   string `json:"path"`
+	Backup string `json:"backup,omitempty"` // relative to the record's trash directory; empty if the path didn't exist
+}
+
+// Begin starts a transaction, keeping the originals of tracked paths under trashDir.
+func Begin(trashDir, description string) (*Transaction, error) {
+	now := time.Now()
+
+	err := os.MkdirAll(trashDir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("cannot begin transaction: %w", err)
+	}
+
+	dir, err := os.MkdirTemp(trashDir, now.UTC().Format("20060102T150405")+"-*")
+	if err != nil {
+		return nil, fmt.Errorf("cannot begin transaction: %w", err)
+	}
+
+	return &Transaction{
+		trashDir: trashDir,
+		record:   Record{ID: filepath.Base(dir), Description: description, Time: now},
+		tracked:  map[string]bool{},
+	}, nil
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+func (t *Transaction) dir() string {
+	return filepath.Join(t.trashDir, t.record.ID)
+}
+
+// track records path, preserving its current state with keep, which moves or copies it to the backup path.
+func (t *Transaction) track(path string, keep func(path, backup string) error) (string, bool, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", false, err
+	}
+
+	if t.tracked[path] {
+		return path, false, nil
+	}
+	t.tracked[path] = true
+
+	if !exists(path) {
+		// track the outermost missing directory instead, so rollback also removes directories created on the way.
+		for parent := filepath.Dir(path); parent != path && !exists(parent); parent = filepath.Dir(parent) {
+			path = parent
+		}
+
+		t.record.Entries = append(t.record.Entries, Entry{Path: path})
+		return path, false, nil
+	}
+
+	backup := strconv.Itoa(len(t.record.Entries))
+	err = keep(path, filepath.Join(t.dir(), backup))
+	if err != nil {
+		return "", false, fmt.Errorf("cannot back up %s: %w", path, err)
+	}
+
+	t.record.Entries = append(t.record.Entries, Entry{Path: path, Backup: backup})
+	return path, true, nil
+}
+
+// Track preserves the current state of path (a file or directory, which may not exist yet), so Rollback can restore it.
+func (t *Transaction) Track(path string) error {
+	_, _, err := t.track(path, copyTree)
+	return err
+}
+
+// Remove moves path into the trash. Removing a path that doesn't exist is not an error.
+func (t *Transaction) Remove(path string) error {
+	path, moved, err := t.track(path, moveTree)
+	if err != nil || moved {
+		return err
+	}
+
+	// already tracked (or missing); its original is safe either way.
+	return os.RemoveAll(path)
+}
+
+// joinErrors combines errors into one, wrapping the first.
+func joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	out := errs[0]
+	for _, err := range errs[1:] {
+		out = fmt.Errorf("%w; %s", out, err.Error())
+	}
+
+	return out
+}
+
+// restore puts every entry of a transaction back the way it was, newest first.
+// Only safe while the transaction is open; nothing else can have changed its paths yet.
+func restore(dir string, entries []Entry) error {
+	var errs []error
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+
+		if err := os.RemoveAll(e.Path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if e.Backup != "" {
+			if err := moveTree(filepath.Join(dir, e.Backup), e.Path); err != nil {
+				errs = append(errs, fmt.Errorf("cannot restore %s: %w", e.Path, err))
+			}
+		}
+	}
+
+	return joinErrors(errs)
+}
+
+// Rollback restores every tracked path to the state it was in when tracked, then ends the transaction.
+func (t *Transaction) Rollback() error {
+	err := restore(t.dir(), t.record.Entries)
+	if err != nil {
+		// keep the originals around, rather than lose what couldn't be restored.
+		return fmt.Errorf("%w\noriginals are kept in %s", err, t.dir())
+	}
+
+	return os.RemoveAll(t.dir())
+}
+
+// Commit keeps the changes and ends the transaction, journaling it so it can be undone.
+func (t *Transaction) Commit() error {
+	journal, err := ReadJournal(t.trashDir)
+	if err != nil {
+		return err
+	}
+
+	journal = append(journal, t.record)
+	for len(journal) > MaxJournal {
+		_ = os.RemoveAll(filepath.Join(t.trashDir, journal[0].ID))
+		journal = journal[1:]
+	}
+
+	return writeJournal(t.trashDir, journal)
+}
+
+func journalPath(trashDir string) string {
+	return filepath.Join(trashDir, "journal.json")
+}
+
+// ReadJournal lists the committed transactions in trashDir, oldest first.
+func ReadJournal(trashDir string) ([]Record, error) {
+	buf, err := os.ReadFile(journalPath(trashDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot read journal: %w", err)
+	}
+
+	journal := make([]Record, 0)
+	err = json.Unmarshal(buf, &journal)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse journal: %w", err)
+	}
+
+	return journal, nil
+}
+
+func writeJournal(trashDir string, journal []Record) error {
+	buf, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = util.WriteFileAtomic(journalPath(trashDir), buf, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot write journal: %w", err)
+	}
+
+	return nil
+}
+
+// undoPrefix names the transactions Undo commits; undoing one of those redoes the original.
+const undoPrefix = "undo "
+
+// undo restores a committed record's originals. Whatever is at its paths now (e.g. edits made since) is moved
+// into a new transaction rather than deleted, and that transaction is committed, so the undo can itself be undone.
+func undo(trashDir string, record Record) error {
+	description := undoPrefix + record.Description
+	if strings.HasPrefix(record.Description, undoPrefix) {
+		description = strings.TrimPrefix(record.Description, undoPrefix)
+	}
+
+	tx, err := Begin(trashDir, description)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Join(trashDir, record.ID)
+	for i := len(record.Entries) - 1; i >= 0; i-- {
+		e := record.Entries[i]
+
+		err = tx.Remove(e.Path)
+		if err == nil && e.Backup != "" {
+			// copied, so the record is intact if a later entry fails.
+			err = copyTree(filepath.Join(dir, e.Backup), e.Path)
+		}
+
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return fmt.Errorf("%w: rollback failed: %s", err, rbErr.Error())
+			}
+			return err
+		}
+	}
+
+	journal, err := ReadJournal(trashDir)
+	if err != nil {
+		return err
+	}
+
+	for i := range journal {
+		if journal[i].ID == record.ID {
+			journal = append(journal[:i], journal[i+1:]...)
+			break
+		}
+	}
+
+	err = writeJournal(trashDir, journal)
+	if err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(dir)
+}
+
+// Undo reverts the last n committed transactions in trashDir, newest first, and returns the ones it undid.
+// Each undo is journaled in turn, so running Undo again redoes them. It stops at the first transaction that can't be restored.
+func Undo(trashDir string, n int) ([]Record, error) {
+	journal, err := ReadJournal(trashDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// picked up front, so the undos journaled along the way aren't undone in turn.
+	if n > len(journal) {
+		n = len(journal)
+	}
+	targets := journal[len(journal)-n:]
+
+	undone := make([]Record, 0, n)
+	for i := len(targets) - 1; i >= 0; i-- {
+		err = undo(trashDir, targets[i])
+		if err != nil {
+			return undone, fmt.Errorf("cannot undo %s: %w", targets[i].Description, err)
+		}
+
+		undone = append(undone, targets[i])
+	}
+
+	return undone, nil
+}
+
+// moveTree moves a file or directory, copying it if it can't be renamed (e.g. across filesystems).
+func moveTree(src, dst string) error {
+	err := os.MkdirAll(filepath.Dir(dst), 0755)
+	if err != nil {
+		return err
+	}
+
+	if os.Rename(src, dst) == nil {
+		return nil
+	}
+
+	err = copyTree(src, dst)
+	if err != nil {
+		return err
+	}
+
+	return os.RemoveAll(src)
+}
+
+// copyTree copies a file or directory, preserving modes.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+
+		err = os.MkdirAll(filepath.Dir(target), 0755)
+		if err != nil {
+			return err
+		}
+
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+
+		out, err := os.OpenFile(target, os.O_RDWR|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+
+		_, err = io.Copy(out, in)
+		if err != nil {
+			_ = out.Close()
+			return err
+		}
+
+		return out.Close()
+	})
+}
@@ -0,0 +1,278 @@
+package transaction
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeFiles creates files under root, keyed by slash-separated relative path.
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// snapshot reads every file and directory under root; directories map to "/".
+func snapshot(t *testing.T, root string) map[string]string {
+	t.Helper()
+
+	out := map[string]string{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == root {
+			return err
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		if d.IsDir() {
+			out[filepath.ToSlash(rel)] = "/"
+			return nil
+		}
+
+		buf, err := os.ReadFile(path)
+		out[filepath.ToSlash(rel)] = string(buf)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return out
+}
+
+func assertSnapshot(t *testing.T, when, root string, want map[string]string) {
+	t.Helper()
+
+	if got := snapshot(t, root); !reflect.DeepEqual(got, want) {
+		t.Errorf("%s:\n got %v\nwant %v", when, got, want)
+	}
+}
+
+func journalDescriptions(t *testing.T, trashDir string) []string {
+	t.Helper()
+
+	journal, err := ReadJournal(trashDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := make([]string, 0)
+	for _, r := range journal {
+		out = append(out, r.Description)
+	}
+
+	return out
+}
+
+func TestRollbackPartway(t *testing.T) {
+	root, trash := t.TempDir(), t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"code/2022/day1/day1.go": "day 1",
+		"code/importer.go":       "imports day 1",
+	})
+	before := snapshot(t, root)
+
+	tx, err := Begin(trash, "partway")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rewrite a file, create one in a new directory, and remove a directory...
+	importer := filepath.Join(root, "code/importer.go")
+	if err := tx.Track(importer); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, root, map[string]string{"code/importer.go": "imports day 2"})
+
+	if err := tx.Track(filepath.Join(root, "code/2023/day2/day2.go")); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, root, map[string]string{"code/2023/day2/day2.go": "day 2"})
+
+	if err := tx.Remove(filepath.Join(root, "code/2022/day1")); err != nil {
+		t.Fatal(err)
+	}
+
+	// ...then fail to write through a file, as if a later step broke.
+	if err := os.WriteFile(filepath.Join(importer, "nope"), nil, 0644); err == nil {
+		t.Fatal("expected writing beneath a file to fail")
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+
+	assertSnapshot(t, "after rollback", root, before)
+
+	if entries, _ := os.ReadDir(trash); len(entries) != 0 {
+		t.Errorf("rollback left %d entries in the trash", len(entries))
+	}
+}
+
+func TestUndoCreate(t *testing.T) {
+	root, trash := t.TempDir(), t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"code/importer.go": "imports nothing",
+		"cache/2022/1.txt": "input",
+	})
+	before := snapshot(t, root)
+
+	tx, err := Begin(trash, "create 2022/1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"code/2022/day1/day1.go", "code/importer.go", "cache/2022/1.solution.txt"} {
+		if err := tx.Track(filepath.Join(root, filepath.FromSlash(name))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFiles(t, root, map[string]string{
+		"code/2022/day1/day1.go":    "day 1",
+		"code/importer.go":          "imports day 1",
+		"cache/2022/1.solution.txt": "42",
+	})
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	after := snapshot(t, root)
+
+	undone, err := Undo(trash, 1)
+	if err != nil || len(undone) != 1 || undone[0].Description != "create 2022/1" {
+		t.Fatalf("Undo = %v, %v", undone, err)
+	}
+	assertSnapshot(t, "after undo", root, before)
+
+	if got := journalDescriptions(t, trash); !reflect.DeepEqual(got, []string{"undo create 2022/1"}) {
+		t.Errorf("journal after undo = %v", got)
+	}
+
+	// undoing the undo redoes the create.
+	if _, err := Undo(trash, 1); err != nil {
+		t.Fatal(err)
+	}
+	assertSnapshot(t, "after redo", root, after)
+
+	if got := journalDescriptions(t, trash); !reflect.DeepEqual(got, []string{"create 2022/1"}) {
+		t.Errorf("journal after redo = %v", got)
+	}
+}
+
+func TestUndoMove(t *testing.T) {
+	root, trash := t.TempDir(), t.TempDir()
+	writeFiles(t, root, map[string]string{
+		"code/2022/day1/day1.go":    "day 1",
+		"code/importer.go":          "imports 2022/day1",
+		"cache/2022/1.txt":          "input",
+		"cache/2022/1.solution.txt": "42",
+	})
+	before := snapshot(t, root)
+
+	tx, err := Begin(trash, "move 2022/1 to 2023/2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rename := func(from, to string) {
+		t.Helper()
+
+		from, to = filepath.Join(root, filepath.FromSlash(from)), filepath.Join(root, filepath.FromSlash(to))
+		for _, path := range []string{from, to} {
+			if err := tx.Track(path); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(from, to); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rename("code/2022/day1", "code/2023/day2")
+	if err := tx.Remove(filepath.Join(root, "code/2022")); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Track(filepath.Join(root, "code/importer.go")); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, root, map[string]string{"code/2023/day2/day1.go": "day 2", "code/importer.go": "imports 2023/day2"})
+	rename("cache/2022/1.txt", "cache/2023/2.txt")
+	rename("cache/2022/1.solution.txt", "cache/2023/2.solution.txt")
+
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Undo(trash, 1); err != nil {
+		t.Fatal(err)
+	}
+	assertSnapshot(t, "after undo", root, before)
+}
+
+func TestJournalSurvivesInterruptedWrite(t *testing.T) {
+	root, trash := t.TempDir(), t.TempDir()
+	path := filepath.Join(root, "file")
+
+	commit := func(description, content string) {
+		t.Helper()
+
+		tx, err := Begin(trash, description)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.Track(path); err != nil {
+			t.Fatal(err)
+		}
+		writeFiles(t, root, map[string]string{"file": content})
+		if err := tx.Commit(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	commit("first", "1")
+
+	// a crash after writing the new journal, but before swapping it in, leaves this behind.
+	if err := os.WriteFile(journalPath(trash)+".tmp", []byte(`[{"id": "trunc`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := journalDescriptions(t, trash); !reflect.DeepEqual(got, []string{"first"}) {
+		t.Errorf("journal after an interrupted write = %v", got)
+	}
+
+	commit("second", "2")
+	if got := journalDescriptions(t, trash); !reflect.DeepEqual(got, []string{"first", "second"}) {
+		t.Errorf("journal after the next commit = %v", got)
+	}
+
+	if _, err := os.Stat(journalPath(trash) + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("the leftover journal was not swapped in: %v", err)
+	}
+
+	if _, err := Undo(trash, 2); err != nil {
+		t.Fatal(err)
+	}
+	assertSnapshot(t, "after undoing both", root, map[string]string{})
+}
+
+func TestUndoEmptyJournal(t *testing.T) {
+	for _, trash := range []string{t.TempDir(), filepath.Join(t.TempDir(), "missing")} {
+		undone, err := Undo(trash, 3)
+		if err != nil || len(undone) != 0 {
+			t.Errorf("Undo(%s) = %v, %v; want nothing undone", trash, undone, err)
+		}
+	}
+}
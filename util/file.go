@@ -0,0 +1,28 @@
+package util
+
+import "os"
+
+// ReplaceFile has write produce the new contents of name in a temporary file beside it, then renames that over name.
+// Readers see either the old file or the new one, never a half-written one, and a failed write leaves name untouched.
+func ReplaceFile(name string, write func(tmpName string) error) error {
+	tmpName := name + ".tmp"
+
+	err := write(tmpName)
+	if err == nil {
+		err = os.Rename(tmpName, name)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+
+	return nil
+}
+
+// WriteFileAtomic is os.WriteFile, replacing name via ReplaceFile.
+func WriteFileAtomic(name string, data []byte, perm os.FileMode) error {
+	return ReplaceFile(name, func(tmpName string) error {
+		return os.WriteFile(tmpName, data, perm)
+	})
+}
@@ -0,0 +1,161 @@
+package util
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AoC draws some answers as block letters, in one of two fonts: 6 rows high (letters ~4 wide),
+// and 10 rows high (letters ~6 wide). Letters are separated by at least one blank column, so glyphs are
+// stored trimmed, and letters of either font are found by splitting on blank columns.
+
+var ocrFont6 = map[string]rune{
+	".##.\n#..#\n#..#\n####\n#..#\n#..#":       'A',
+	"###.\n#..#\n###.\n#..#\n#..#\n###.":       'B',
+	".##.\n#..#\n#...\n#...\n#..#\n.##.":       'C',
+	"####\n#...\n###.\n#...\n#...\n####":       'E',
+	"####\n#...\n###.\n#...\n#...\n#...":       'F',
+	".##.\n#..#\n#...\n#.##\n#..#\n.###":       'G',
+	"#..#\n#..#\n####\n#..#\n#..#\n#..#":       'H',
+	"###\n.#.\n.#.\n.#.\n.#.\n###":             'I',
+	"..##\n...#\n...#\n...#\n#..#\n.##.":       'J',
+	"#..#\n#.#.\n##..\n#.#.\n#.#.\n#..#":       'K',
+	"#...\n#...\n#...\n#...\n#...\n####":       'L',
+	".##.\n#..#\n#..#\n#..#\n#..#\n.##.":       'O',
+	"###.\n#..#\n#..#\n###.\n#...\n#...":       'P',
+	"###.\n#..#\n#..#\n###.\n#.#.\n#..#":       'R',
+	".###\n#...\n#...\n.##.\n...#\n###.":       'S',
+	"#..#\n#..#\n#..#\n#..#\n#..#\n.##.":       'U',
+	"#...#\n#...#\n.#.#.\n..#..\n..#..\n..#..": 'Y',
+	"####\n...#\n..#.\n.#..\n#...\n####":       'Z',
+}
+
+var ocrFont10 = map[string]rune{
+	"..##..\n.#..#.\n#....#\n#....#\n#....#\n######\n#....#\n#....#\n#....#\n#....#": 'A',
+	"#####.\n#....#\n#....#\n#....#\n#####.\n#....#\n#....#\n#....#\n#....#\n#####.": 'B',
+	".####.\n#....#\n#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n#....#\n.####.": 'C',
+	"######\n#.....\n#.....\n#.....\n#####.\n#.....\n#.....\n#.....\n#.....\n######": 'E',
+	"######\n#.....\n#.....\n#.....\n#####.\n#.....\n#.....\n#.....\n#.....\n#.....": 'F',
+	".####.\n#....#\n#.....\n#.....\n#.....\n#..###\n#....#\n#....#\n#...##\n.###.#": 'G',
+	"#....#\n#....#\n#....#\n#....#\n######\n#....#\n#....#\n#....#\n#....#\n#....#": 'H',
+	"...###\n....#.\n....#.\n....#.\n....#.\n....#.\n....#.\n#...#.\n#...#.\n.###..": 'J',
+	"#....#\n#...#.\n#..#..\n#.#...\n##....\n##....\n#.#...\n#..#..\n#...#.\n#....#": 'K',
+	"#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n######": 'L',
+	"#....#\n##...#\n##...#\n#.#..#\n#.#..#\n#..#.#\n#..#.#\n#...##\n#...##\n#....#": 'N',
+	"#####.\n#....#\n#....#\n#....#\n#####.\n#.....\n#.....\n#.....\n#.....\n#.....": 'P',
+	"#####.\n#....#\n#....#\n#....#\n#####.\n#..#..\n#...#.\n#...#.\n#....#\n#....#": 'R',
+	"#....#\n#....#\n.#..#.\n.#..#.\n..##..\n..##..\n.#..#.\n.#..#.\n#....#\n#....#": 'X',
+	"######\n.....#\n.....#\n....#.\n...#..\n..#...\n.#....\n#.....\n#.....\n######": 'Z',
+}
+
+// ParseGrid reads a drawn grid, treating '#' and '█' as lit and anything else as unlit.
+func ParseGrid(s string) [][]bool {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	out := make([][]bool, 0, len(lines))
+
+	for _, line := range lines {
+		row := make([]bool, 0, len(line))
+		for _, c := range line {
+			row = append(row, c == '#' || c == '█')
+		}
+		out = append(out, row)
+	}
+
+	return out
+}
+
+// trimGrid removes blank rows from the top and bottom, and pads every row to the same width.
+func trimGrid(grid [][]bool) [][]bool {
+	blank := func(row []bool) bool {
+		for _, v := range row {
+			if v {
+				return false
+			}
+		}
+		return true
+	}
+
+	for len(grid) > 0 && blank(grid[0]) {
+		grid = grid[1:]
+	}
+	for len(grid) > 0 && blank(grid[len(grid)-1]) {
+		grid = grid[:len(grid)-1]
+	}
+
+	width := 0
+	for _, row := range grid {
+		width = Ternary(len(row) > width, len(row), width)
+	}
+
+	out := make([][]bool, len(grid))
+	for y, row := range grid {
+		out[y] = make([]bool, width)
+		copy(out[y], row)
+	}
+
+	return out
+}
+
+// OCR reads the block letters in a grid of lit (true) and unlit pixels.
+func OCR(grid [][]bool) (string, error) {
+	grid = trimGrid(grid)
+	if len(grid) == 0 {
+		return "", errors.New("grid is empty")
+	}
+
+	var font map[string]rune
+	switch len(grid) {
+	case 6:
+		font = ocrFont6
+	case 10:
+		font = ocrFont10
+	default:
+		return "", fmt.Errorf("no font is %d rows high", len(grid))
+	}
+
+	columnLit := func(x int) bool {
+		for _, row := range grid {
+			if row[x] {
+				return true
+			}
+		}
+		return false
+	}
+
+	out := strings.Builder{}
+	width := len(grid[0])
+	for x := 0; x < width; {
+		if !columnLit(x) {
+			x++
+			continue
+		}
+
+		start := x
+		for x < width && columnLit(x) {
+			x++
+		}
+
+		glyph := make([]string, len(grid))
+		for y, row := range grid {
+			line := strings.Builder{}
+			for _, lit := range row[start:x] {
+				line.WriteByte(Ternary[byte](lit, '#', '.'))
+			}
+			glyph[y] = line.String()
+		}
+
+		letter, ok := font[strings.Join(glyph, "\n")]
+		if !ok {
+			return "", fmt.Errorf("unrecognized letter at column %d", start)
+		}
+		out.WriteRune(letter)
+	}
+
+	return out.String(), nil
+}
+
+// OCRString reads the block letters in a drawn grid. See ParseGrid.
+func OCRString(s string) (string, error) {
+	return OCR(ParseGrid(s))
+}
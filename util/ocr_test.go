@@ -0,0 +1,87 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+// beside draws glyphs side by side, one blank column apart.
+func beside(glyphs ...string) string {
+	var rows [][]string
+	for _, g := range glyphs {
+		for y, line := range strings.Split(g, "\n") {
+			if y == len(rows) {
+				rows = append(rows, nil)
+			}
+			rows[y] = append(rows[y], line)
+		}
+	}
+
+	out := make([]string, len(rows))
+	for y, row := range rows {
+		out[y] = strings.Join(row, ".")
+	}
+
+	return strings.Join(out, "\n")
+}
+
+const (
+	glyph6H  = "#..#\n#..#\n####\n#..#\n#..#\n#..#"
+	glyph6I  = "###\n.#.\n.#.\n.#.\n.#.\n###"
+	glyph10L = "#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n#.....\n######"
+	glyph10Z = "######\n.....#\n.....#\n....#.\n...#..\n..#...\n.#....\n#.....\n#.....\n######"
+)
+
+func TestOCRString(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"6 rows", beside(glyph6H, glyph6I), "HI"},
+		{"10 rows", beside(glyph10L, glyph10Z), "LZ"},
+		{"trailing newlines", beside(glyph6H, glyph6I) + "\n\n", "HI"},
+		{"blank rows above", "....\n" + glyph6H, "H"},
+		{"block characters", strings.ReplaceAll(beside(glyph6H, glyph6I), "#", "█"), "HI"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := OCRString(tt.in)
+			if err != nil || got != tt.want {
+				t.Errorf("OCRString(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOCRStringErrors(t *testing.T) {
+	tests := map[string]string{
+		"empty":         "\n\n",
+		"unknown glyph": beside(glyph6H, "####\n####\n####\n####\n####\n####"),
+		"no such font":  "#\n#\n#",
+	}
+
+	for name, in := range tests {
+		if got, err := OCRString(in); err == nil {
+			t.Errorf("%s: OCRString(%q) = %q, want an error", name, in, got)
+		}
+	}
+}
+
+func TestOCRRaggedGrid(t *testing.T) {
+	// rows of a [][]bool may stop at their last lit pixel.
+	grid := ParseGrid(glyph6H)
+	for y, row := range grid {
+		end := len(row)
+		for end > 0 && !row[end-1] {
+			end--
+		}
+		grid[y] = row[:end]
+	}
+
+	got, err := OCR(grid)
+	if err != nil || got != "H" {
+		t.Errorf("OCR(ragged H) = %q, %v", got, err)
+	}
+}